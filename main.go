@@ -17,6 +17,8 @@ func main() {
 	useRotate := flag.Bool("r", false, "Rotates the object to have y use depth and z height")
 	exportPtr := flag.String("name", "", "name of the file to export to in pkg/examples")
 	runExample := flag.Bool("e", false, "Runs specified example")
+	serveAddr := flag.String("serve", "", "address to serve the tiles of the parsed scene to workers on, e.g. :8080")
+	workerURL := flag.String("worker", "", "URL of the coordinator to render tiles of the parsed scene for")
 	flag.Parse()
 
 	log.Println("==================== Golang ray tracer V 0.1 ====================")
@@ -26,26 +28,45 @@ func main() {
 			return
 		}
 
-		parseObj(*fileNamePtr, *exportPtr, *useRotate)
+		if *serveAddr != "" {
+			name := *exportPtr
+			if name == "" {
+				name = "example"
+			}
+			err := examples.CreateDistributedScene(name, *serveAddr)
+			if err != nil {
+				log.Println(err)
+			}
+			return
+		}
+		parseObj(*fileNamePtr, *exportPtr, *useRotate, *workerURL)
 
 	} else if *runExample {
 		log.Println("This should run an example")
 	}
 }
 
-//parseObj is called on a fileName from the CLI if used with the -p tag
-func parseObj(filePathName string, newName string, rotate bool) {
+//parseObj is called on a fileName from the CLI if used with the -p tag, with a worker URL the scene's tiles are
+// rendered for that coordinator instead
+func parseObj(filePathName string, newName string, rotate bool, workerURL string) {
 	p := parser.ParseObjFile(filePathName)
 	g := p.ToGeometry(rotate)
 
 	w := &geometry.World{}
 	objs := make([]primitives.Shape, 0, 0)
 	objs = append(objs, g)
-	lights := make([]*canvas.PointLight, 0, 0)
+	lights := make([]canvas.Light, 0, 0)
 	lights = append(lights, canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-20, 10, -20)))
 	w.Lights = lights
 	w.Objects = objs
 
+	if workerURL != "" {
+		err := examples.RunSceneWorker(w, workerURL)
+		if err != nil {
+			log.Println(err)
+		}
+		return
+	}
 	if newName != "" {
 		err := examples.CreateCustomScene(w, newName, rotate)
 		if err != nil {
@@ -3,6 +3,7 @@ package algebra
 type Ray struct {
 	origin    *Vector
 	direction *Vector
+	time      float64 // instant within the shutter interval the ray is cast at, used for motion blur
 }
 
 //NewRay returns a 3D ray composed of a origin point Vector and a direction vector Vector
@@ -42,5 +43,15 @@ func (r *Ray) Transform(m *Matrix) *Ray {
 	direction := v["direction"]
 	origin2 := m.MultiplyByVec(origin)
 	direction2 := m.MultiplyByVec(direction)
-	return &Ray{origin: origin2, direction: direction2}
+	return &Ray{origin: origin2, direction: direction2, time: r.time}
+}
+
+//Time returns the instant the ray is cast at, rays are cast at time 0 unless set otherwise
+func (r *Ray) Time() float64 {
+	return r.time
+}
+
+//SetTime sets the instant the ray is cast at
+func (r *Ray) SetTime(t float64) {
+	r.time = t
 }
@@ -57,3 +57,15 @@ func TestRay_Transform(t *testing.T) {
 	direction = []float64{0, 3, 0}
 	testVectorEquals(t, v["direction"].Get(), direction)
 }
+
+func TestRay_Time(t *testing.T) {
+	r := NewRay(1, 2, 3, 0, 1, 0)
+	if r.Time() != 0 {
+		t.Errorf("Expected new rays to be cast at time 0, got %f", r.Time())
+	}
+	r.SetTime(0.25)
+	r2 := r.Transform(TranslationMatrix(3, 4, 5))
+	if r2.Time() != 0.25 {
+		t.Errorf("Expected transformed ray to keep time %f, got %f", 0.25, r2.Time())
+	}
+}
@@ -207,3 +207,29 @@ func (v *Vector) Reflect(normal *Vector) *Vector {
 	}
 	return reflect
 }
+
+//OrthonormalBasis returns two unit vectors perpendicular to each other and to the vector w, such that they form a
+// right handed basis with w. Any basis is returned for the zero vector
+func OrthonormalBasis(w *Vector) (*Vector, *Vector) {
+	n, err := NewVector(w.tuple[0], w.tuple[1], w.tuple[2]).Normalize()
+	if err != nil {
+		return NewVector(1, 0, 0), NewVector(0, 1, 0)
+	}
+	helper := NewVector(1, 0, 0)
+	if math.Abs(n.tuple[0]) > 0.9 {
+		helper = NewVector(0, 1, 0)
+	}
+	u, err := CrossProduct(helper, n)
+	if err != nil {
+		panic(err)
+	}
+	u, err = u.Normalize()
+	if err != nil {
+		panic(err)
+	}
+	v, err := CrossProduct(n, u)
+	if err != nil {
+		panic(err)
+	}
+	return u, v
+}
@@ -281,3 +281,22 @@ func testVectorEquals(t *testing.T, values, results []float64) {
 		}
 	}
 }
+
+func TestOrthonormalBasis(t *testing.T) {
+	for _, w := range []*Vector{NewVector(0, 0, 2), NewVector(1, 0, 0), NewVector(1, -2, 3)} {
+		u, v := OrthonormalBasis(w)
+		n, _ := w.Normalize()
+		uv, _ := DotProduct(u, v)
+		un, _ := DotProduct(u, n)
+		vn, _ := DotProduct(v, n)
+		if !equals(u.Magnitude(), 1) || !equals(v.Magnitude(), 1) || !equals(uv, 0) || !equals(un, 0) || !equals(vn, 0) {
+			t.Errorf("Expected an orthonormal basis around %v, got %v and %v", w.Get(), u.Get(), v.Get())
+		}
+		cross, _ := CrossProduct(u, v)
+		for i := 0; i < 3; i++ {
+			if !equals(cross.Get()[i], n.Get()[i]) {
+				t.Errorf("Expected the basis around %v to be right handed", w.Get())
+			}
+		}
+	}
+}
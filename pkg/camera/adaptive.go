@@ -0,0 +1,127 @@
+package camera
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"math"
+	"math/rand"
+)
+
+//AdaptiveSampler supersamples only where the image has contrast. Each pixel is traced at its four corners, which
+// it shares with its neighbours, and a square is split in four, recursively, whenever two of its corner samples
+// differ by more than Threshold
+type AdaptiveSampler struct {
+	Threshold float64 // largest difference in any color channel tolerated between the corners of a square
+	MaxDepth  int     // maximum number of times a pixel is subdivided
+}
+
+//NewAdaptiveSampler returns a new AdaptiveSampler with the given color threshold and maximum subdivision depth
+func NewAdaptiveSampler(threshold float64, maxDepth int) *AdaptiveSampler {
+	return &AdaptiveSampler{Threshold: threshold, MaxDepth: maxDepth}
+}
+
+//SetAdaptiveSampler sets the adaptive supersampling settings, nil disables adaptive supersampling
+func (r *Renderer) SetAdaptiveSampler(a *AdaptiveSampler) {
+	r.adaptive = a
+}
+
+//sampleTile computes the colors of the pixels of the tile, indexed by row then column from its top left pixel,
+// trace returns the color seen through a point of the image plane using the random numbers of rng. The (w+1)×(h+1)
+// corners of the pixels are traced once into a grid shared by neighbouring pixels and only the pixels whose corners
+// differ by more than Threshold trace more rays. Each corner is traced with the random numbers of the pixel it is the
+// top left corner of, which go on to refine that pixel, so corners shared with the neighbouring tiles come out the same
+// and the image does not depend on the tiles. The rays beyond the corner grid are counted as refinement rays
+func (a *AdaptiveSampler) sampleTile(t Tile, seed int64, trace func(px, py float64, rng *rand.Rand) *canvas.Color) ([][]*canvas.Color, rayCount) {
+	rngs := make([][]*rand.Rand, t.Height+1)
+	corners := make([][]*canvas.Color, t.Height+1)
+	for j := range corners {
+		rngs[j] = make([]*rand.Rand, t.Width+1)
+		corners[j] = make([]*canvas.Color, t.Width+1)
+		for i := range corners[j] {
+			x, y := t.X+i, t.Y+j
+			rngs[j][i] = pixelRand(seed, x, y)
+			corners[j][i] = trace(float64(x), float64(y), rngs[j][i])
+		}
+	}
+	rays := rayCount{rays: int64((t.Width + 1) * (t.Height + 1))}
+	colors := make([][]*canvas.Color, t.Height)
+	for j := range colors {
+		colors[j] = make([]*canvas.Color, t.Width)
+		for i := range colors[j] {
+			rng := rngs[j][i]
+			color, n := a.samplePixel(t.X+i, t.Y+j,
+				[4]*canvas.Color{corners[j][i], corners[j][i+1], corners[j+1][i], corners[j+1][i+1]},
+				func(px, py float64) *canvas.Color {
+					return trace(px, py, rng)
+				})
+			colors[j][i] = color
+			rays = rays.add(n)
+		}
+	}
+	return colors, rays
+}
+
+//samplePixel computes the color of pixel (x, y) from the colors of its top left, top right, bottom left and bottom
+// right corners, trace returns the color seen through a point of the image plane. Corners shared by the subdivided
+// squares of the pixel are only traced once. Every ray it traces is a refinement ray
+func (a *AdaptiveSampler) samplePixel(x, y int, corners [4]*canvas.Color, trace func(px, py float64) *canvas.Color) (*canvas.Color, rayCount) {
+	fx, fy := float64(x), float64(y)
+	samples := map[[2]float64]*canvas.Color{
+		{fx, fy}:         corners[0],
+		{fx + 1, fy}:     corners[1],
+		{fx, fy + 1}:     corners[2],
+		{fx + 1, fy + 1}: corners[3],
+	}
+	sample := func(px, py float64) *canvas.Color {
+		key := [2]float64{px, py}
+		if color, ok := samples[key]; ok {
+			return color
+		}
+		color := trace(px, py)
+		samples[key] = color
+		return color
+	}
+	color := a.sampleSquare(fx, fy, 1, 0, sample)
+	refinement := int64(len(samples) - 4)
+	return color, rayCount{rays: refinement, refinement: refinement}
+}
+
+//sampleSquare returns the color of the square with top left corner (x, y) and the given size
+func (a *AdaptiveSampler) sampleSquare(x, y, size float64, depth int, sample func(px, py float64) *canvas.Color) *canvas.Color {
+	corners := []*canvas.Color{
+		sample(x, y),
+		sample(x+size, y),
+		sample(x, y+size),
+		sample(x+size, y+size),
+	}
+	if depth >= a.MaxDepth || maxColorDifference(corners) <= a.Threshold {
+		return averageColor(corners)
+	}
+	half := size / 2
+	return averageColor([]*canvas.Color{
+		a.sampleSquare(x, y, half, depth+1, sample),
+		a.sampleSquare(x+half, y, half, depth+1, sample),
+		a.sampleSquare(x, y+half, half, depth+1, sample),
+		a.sampleSquare(x+half, y+half, half, depth+1, sample),
+	})
+}
+
+//maxColorDifference returns the largest difference in any channel between any two of the colors
+func maxColorDifference(colors []*canvas.Color) float64 {
+	diff := 0.0
+	for i := 0; i < len(colors); i++ {
+		for j := i + 1; j < len(colors); j++ {
+			for k := 0; k < 3; k++ {
+				diff = math.Max(diff, math.Abs(colors[i][k]-colors[j][k]))
+			}
+		}
+	}
+	return diff
+}
+
+func averageColor(colors []*canvas.Color) *canvas.Color {
+	sum := &canvas.Color{0, 0, 0}
+	for _, color := range colors {
+		sum = sum.Add(color)
+	}
+	return sum.ScalarMult(1 / float64(len(colors)))
+}
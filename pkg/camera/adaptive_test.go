@@ -0,0 +1,115 @@
+package camera
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAdaptiveSampler_samplePixel(t *testing.T) {
+	a := NewAdaptiveSampler(0.1, 2)
+	flat := func(px, py float64) *canvas.Color {
+		return &canvas.Color{0.5, 0.5, 0.5}
+	}
+	corners := func(trace func(px, py float64) *canvas.Color) [4]*canvas.Color {
+		return [4]*canvas.Color{trace(3, 4), trace(4, 4), trace(3, 5), trace(4, 5)}
+	}
+	color, rays := a.samplePixel(3, 4, corners(flat), flat)
+	testVectorEquals(t, color[:], []float64{0.5, 0.5, 0.5})
+	if rays.rays != 0 || rays.refinement != 0 {
+		t.Errorf("Expected a flat pixel to only use its 4 corners, got %d rays", rays.rays)
+	}
+
+	// a vertical edge a quarter of the way across the pixel
+	edge := func(px, py float64) *canvas.Color {
+		if px < 3.25 {
+			return &canvas.Color{1, 1, 1}
+		}
+		return &canvas.Color{0, 0, 0}
+	}
+	color, rays = a.samplePixel(3, 4, corners(edge), edge)
+	if rays.refinement == 0 {
+		t.Errorf("Expected a pixel on an edge to be refined")
+	}
+	if rays.rays != rays.refinement {
+		t.Errorf("Expected every ray traced past the corners to be a refinement ray")
+	}
+	// corner estimate is 0.5, refinement gets it closer to the covered fraction
+	if math.Abs(color.Red()-0.25) >= 0.25 {
+		t.Errorf("Expected refinement to improve the edge estimate, got %f", color.Red())
+	}
+
+	a = NewAdaptiveSampler(0.1, 0)
+	_, rays = a.samplePixel(3, 4, corners(edge), edge)
+	if rays.refinement != 0 {
+		t.Errorf("Expected no refinement with a maximum depth of 0")
+	}
+}
+
+func TestAdaptiveSampler_sampleTile(t *testing.T) {
+	a := NewAdaptiveSampler(0.1, 2)
+	traced := make(map[[2]float64]int)
+	// a vertical edge through the middle of the third column of pixels
+	edge := func(px, py float64, rng *rand.Rand) *canvas.Color {
+		traced[[2]float64{px, py}]++
+		if px < 2.5 {
+			return &canvas.Color{1, 1, 1}
+		}
+		return &canvas.Color{0, 0, 0}
+	}
+	colors, rays := a.sampleTile(Tile{X: 0, Y: 0, Width: 4, Height: 3}, 0, edge)
+	if len(colors) != 3 || len(colors[0]) != 4 {
+		t.Errorf("Expected 3 rows of 4 pixels, got %d rows of %d", len(colors), len(colors[0]))
+		return
+	}
+	if rays.rays-rays.refinement != 5*4 {
+		t.Errorf("Expected the 5x4 shared corners to be traced once each, got %d rays", rays.rays-rays.refinement)
+	}
+	for point, n := range traced {
+		if point[0] == math.Floor(point[0]) && point[1] == math.Floor(point[1]) && n != 1 {
+			t.Errorf("Expected the corner %v to be traced once, got %d", point, n)
+		}
+	}
+	// only the 3 pixels of the column with the edge are refined: 5 samples split them in four, then the 2 squares
+	// left of the edge are split again with 9 more
+	if rays.refinement != 3*(5+9) {
+		t.Errorf("Expected only the column on the edge to be refined, got %d refinement rays", rays.refinement)
+	}
+	testVectorEquals(t, colors[1][1][:], []float64{1, 1, 1})
+	testVectorEquals(t, colors[1][3][:], []float64{0, 0, 0})
+	if math.Abs(colors[1][2].Red()-0.5) > 0.15 {
+		t.Errorf("Expected the pixel on the edge to be about half covered, got %f", colors[1][2].Red())
+	}
+}
+
+func TestCamera_SetAdaptiveSampler(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c, err := NewCamera(11, 11, math.Pi/2,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	var last Progress
+	c.SetProgress(func(p Progress) {
+		last = p
+	})
+	c.SetAdaptiveSampler(NewAdaptiveSampler(0.05, 3))
+	image := c.Render(w)
+	if last.Refinement <= 0 {
+		t.Errorf("Expected the sphere's silhouette to need refinement rays")
+	}
+	corners := int64(0)
+	for _, tile := range Tiles(11, 11, DEFAULTTILESIZE) {
+		corners += int64((tile.Width + 1) * (tile.Height + 1))
+	}
+	if last.Rays-last.Refinement != corners {
+		t.Errorf("Expected %d corner rays, got %d", corners, last.Rays-last.Refinement)
+	}
+	if *image.Pixels[0][0] != (canvas.Color{0, 0, 0}) {
+		t.Errorf("Expected background pixel to stay black, got %v", *image.Pixels[0][0])
+	}
+}
@@ -1,14 +1,19 @@
 package camera
 
 import (
+	"context"
 	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
 	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
 	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
 	"math"
+	"math/rand"
 )
 
 var RECURSIONDEPTH int = 3
 
+//DEFAULTTILESIZE is the width and height in pixels of the tiles handed to render workers
+var DEFAULTTILESIZE int = 16
+
 //Camera describes a camera object that renders pixels from the setup scene
 type Camera struct {
 	hSize      float64 // horizontal size of picture to be rendered in pixels
@@ -18,6 +23,15 @@ type Camera struct {
 	halfHeight float64
 	pixelSize  float64
 	transform  *algebra.Matrix
+	Renderer
+
+	aperture      float64 // radius of the lens, 0 for a pinhole camera
+	focalDistance float64 // distance from the camera to the plane in focus
+	blades        int     // number of sides of a polygonal aperture, fewer than 3 for a circular aperture
+	bladeRotation float64
+
+	orthographic bool    // parallel projection, halfWidth and halfHeight are then in world units
+	shift        float64 // horizontal shift of the image plane, used by the off-axis eyes of a StereoRig
 }
 
 //NewDefaultCamera returns a new camera with the given size and fov, that has 4x4 identity matrix as
@@ -39,7 +53,8 @@ func NewDefaultCamera(hSize, vSize, fov float64) *Camera {
 		halfWidth:  halfWidth,
 		halfHeight: halfHeight,
 		pixelSize:  pixelSize,
-		transform:  algebra.IdentityMatrix(4)}
+		transform:  algebra.IdentityMatrix(4),
+		Renderer:   *NewRenderer()}
 }
 
 //NewCamera return a new camera with the given size and fov and the provided 4x4 transform matrix
@@ -63,19 +78,39 @@ func NewCamera(hSize, vSize, fov float64, transform *algebra.Matrix) (*Camera, e
 		halfWidth:  halfWidth,
 		halfHeight: halfHeight,
 		pixelSize:  pixelSize,
-		transform:  transform}, nil
+		transform:  transform,
+		Renderer:   *NewRenderer()}, nil
 }
 
+//RayForPixel returns the ray from the camera passing through the centre of the pixel (px, py)
 func (c Camera) RayForPixel(px, py float64) *algebra.Ray {
-	xOffset := (px + 0.5) * c.pixelSize
-	yOffset := (py + 0.5) * c.pixelSize
+	return c.rayForPoint(px+0.5, py+0.5, nil)
+}
 
-	worldX := c.halfWidth - xOffset
+//rayForPoint returns the ray from the camera passing through the point (x, y) of the image plane, in pixel units
+// measured from the top left corner of the image. With a lens aperture the ray starts at a point of the lens
+// sampled with rng and passes through the point of the focal plane seen through (x, y), a nil rng always
+// uses the centre of the lens
+func (c Camera) rayForPoint(x, y float64, rng *rand.Rand) *algebra.Ray {
+	if c.orthographic {
+		return c.orthographicRay(x, y)
+	}
+	xOffset := x * c.pixelSize
+	yOffset := y * c.pixelSize
+
+	worldX := c.halfWidth - xOffset + c.shift
 	worldY := c.halfHeight - yOffset
 
+	lensX, lensY := 0.0, 0.0
+	focus := 1.0
+	if c.aperture > 0 {
+		lensX, lensY = c.sampleLens(rng)
+		focus = c.focalDistance
+	}
+
 	intermediate := c.transform.Inverse()
-	pixel := intermediate.MultiplyByVec(algebra.NewPoint(worldX, worldY, -1))
-	origin := intermediate.MultiplyByVec(algebra.NewPoint(0, 0, 0))
+	pixel := intermediate.MultiplyByVec(algebra.NewPoint(worldX*focus, worldY*focus, -focus))
+	origin := intermediate.MultiplyByVec(algebra.NewPoint(lensX, lensY, 0))
 	direction, err := pixel.Subtract(origin)
 	if err != nil {
 		panic(err)
@@ -92,15 +127,30 @@ func (c Camera) RayForPixel(px, py float64) *algebra.Ray {
 	return algebra.NewRay(res...)
 }
 
+//Size returns the width and height in pixels of the images rendered by the camera
+func (c Camera) Size() (int, int) {
+	return int(c.hSize), int(c.vSize)
+}
+
+//RayAt returns the ray through the point (x, y) of the image plane, in pixel units from the top left corner,
+// rng samples the lens when the camera has an aperture
+func (c Camera) RayAt(x, y float64, rng *rand.Rand) *algebra.Ray {
+	return c.rayForPoint(x, y, rng)
+}
+
+//Render renders the World as seen from the camera, the image is split into tiles that are rendered
+// concurrently by the camera's workers. It panics if the checkpoint state file cannot be read or written
 func (c Camera) Render(w *geometry.World) *canvas.Canvas {
-	image := canvas.NewCanvas(int(c.hSize), int(c.vSize))
-
-	for y := 0.0; y < c.vSize; y++ {
-		for x := 0.0; x < c.hSize; x++ {
-			ray := c.RayForPixel(x, y)
-			color := w.ColorAt(ray, RECURSIONDEPTH)
-			image.WritePixel(int(x), int(y), color)
-		}
+	image, err := c.RenderContext(context.Background(), w)
+	if err != nil {
+		panic(err)
 	}
 	return image
 }
+
+//RenderContext renders the World like Render but stops handing out tiles once ctx is cancelled or its deadline
+// passes. In that case the partially rendered canvas is returned along with a RenderInterrupted error, tiles that
+// were not rendered are left black
+func (c Camera) RenderContext(ctx context.Context, w *geometry.World) (*canvas.Canvas, error) {
+	return c.RenderProjection(ctx, c, w)
+}
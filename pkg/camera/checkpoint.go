@@ -0,0 +1,186 @@
+package camera
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+)
+
+//checkpointMagic starts every checkpoint file, followed by the header and then one record per finished tile
+var checkpointMagic = [8]byte{'R', 'T', 'C', 'K', 'P', 'T', '0', '1'}
+
+//SetCheckpoint saves every finished tile to the state file at path, usually next to the rendered image. A render
+// with the same image size, crop window and tile size resumes from the tiles already saved in the file instead of
+// rendering them again. Other render settings are not recorded so they should not change between runs. An empty path
+// disables checkpoints
+func (r *Renderer) SetCheckpoint(path string) {
+	r.checkpoint = path
+}
+
+//checkpointHeader identifies the render a checkpoint file belongs to
+type checkpointHeader struct {
+	Width, Height int32 // size of the whole image
+	Crop          [4]int32
+	TileSize      int32
+}
+
+func newCheckpointHeader(width, height int, crop Tile, tileSize int) checkpointHeader {
+	return checkpointHeader{Width: int32(width), Height: int32(height),
+		Crop:     [4]int32{int32(crop.X), int32(crop.Y), int32(crop.Width), int32(crop.Height)},
+		TileSize: int32(tileSize)}
+}
+
+//checkpoint is an open state file that finished tiles are appended to, it is safe to use from several workers
+type checkpoint struct {
+	mu    sync.Mutex
+	file  *os.File
+	saved map[Tile][]*canvas.Color // tiles read back from the file when it was opened
+	err   error                    // first error met while saving tiles
+}
+
+//openCheckpoint opens the state file at path, or creates it if there is none. A file written for another render is
+// started over, and a tile record cut short by an interrupted write is dropped
+func openCheckpoint(path string, header checkpointHeader) (*checkpoint, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	c := &checkpoint{file: file, saved: make(map[Tile][]*canvas.Color)}
+	end, err := c.read(header)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if end == 0 {
+		log.Printf("Starting new checkpoint %s", path)
+		if err := c.writeHeader(header); err != nil {
+			file.Close()
+			return nil, err
+		}
+		return c, nil
+	}
+	if err := file.Truncate(end); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(end, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	log.Printf("Resuming from checkpoint %s (%d tiles saved)", path, len(c.saved))
+	return c, nil
+}
+
+//read loads the tiles saved in the file and returns the offset following the last complete record, 0 when the
+// file is empty or was written for another render
+func (c *checkpoint) read(header checkpointHeader) (int64, error) {
+	data, err := ioutil.ReadAll(c.file)
+	if err != nil {
+		return 0, err
+	}
+	buf := bytes.NewReader(data)
+	var magic [8]byte
+	var saved checkpointHeader
+	if binary.Read(buf, binary.LittleEndian, &magic) != nil || magic != checkpointMagic ||
+		binary.Read(buf, binary.LittleEndian, &saved) != nil || saved != header {
+		return 0, nil
+	}
+	end := int64(len(data) - buf.Len())
+	for {
+		var rect [4]int32
+		if binary.Read(buf, binary.LittleEndian, &rect) != nil {
+			return end, nil
+		}
+		t := Tile{X: int(rect[0]), Y: int(rect[1]), Width: int(rect[2]), Height: int(rect[3])}
+		if t.Width < 0 || t.Height < 0 || 24*t.Width*t.Height > buf.Len() {
+			return end, nil
+		}
+		values := make([]float64, 3*t.Width*t.Height)
+		if binary.Read(buf, binary.LittleEndian, values) != nil {
+			return end, nil
+		}
+		pixels := make([]*canvas.Color, 0, t.Width*t.Height)
+		for i := 0; i < len(values); i += 3 {
+			pixels = append(pixels, &canvas.Color{values[i], values[i+1], values[i+2]})
+		}
+		c.saved[t] = pixels
+		end = int64(len(data) - buf.Len())
+	}
+}
+
+func (c *checkpoint) writeHeader(header checkpointHeader) error {
+	if err := c.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := c.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, checkpointMagic)
+	binary.Write(&buf, binary.LittleEndian, header)
+	_, err := c.file.Write(buf.Bytes())
+	return err
+}
+
+//restore copies the saved tiles into image, whose top left pixel is the top left corner of crop, and returns the
+// tiles that still need to be rendered
+func (c *checkpoint) restore(image *canvas.Canvas, crop Tile, tiles []Tile) []Tile {
+	todo := make([]Tile, 0, len(tiles))
+	for _, t := range tiles {
+		pixels, ok := c.saved[t]
+		if !ok {
+			todo = append(todo, t)
+			continue
+		}
+		for y := 0; y < t.Height; y++ {
+			for x := 0; x < t.Width; x++ {
+				image.WritePixel(t.X+x-crop.X, t.Y+y-crop.Y, pixels[y*t.Width+x])
+			}
+		}
+	}
+	return todo
+}
+
+//save appends the pixels of a finished tile to the file, a nil checkpoint does nothing. Each tile is written with a
+// single call so a render killed while saving loses at most that tile
+func (c *checkpoint) save(image *canvas.Canvas, crop Tile, t Tile) {
+	if c == nil {
+		return
+	}
+	values := make([]float64, 0, 3*t.Width*t.Height)
+	for y := t.Y; y < t.Y+t.Height; y++ {
+		for x := t.X; x < t.X+t.Width; x++ {
+			color := image.Pixels[y-crop.Y][x-crop.X]
+			values = append(values, color[0], color[1], color[2])
+		}
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, [4]int32{int32(t.X), int32(t.Y), int32(t.Width), int32(t.Height)})
+	binary.Write(&buf, binary.LittleEndian, values)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	if _, err := c.file.Write(buf.Bytes()); err != nil {
+		c.err = err
+	}
+}
+
+//close closes the state file and returns the first error met while saving tiles
+func (c *checkpoint) close() error {
+	if c == nil {
+		return nil
+	}
+	err := c.file.Close()
+	if c.err != nil {
+		return c.err
+	}
+	return err
+}
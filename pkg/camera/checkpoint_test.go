@@ -0,0 +1,108 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderer_SetCropWindow(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c, err := NewCamera(11, 11, math.Pi/2,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	c.SetTileSize(4)
+	full := c.Render(w)
+
+	c.SetCropWindow(3, 2, 6, 20)
+	crop := c.Render(w)
+	if crop.Width != 6 || crop.Height != 9 {
+		t.Errorf("Expected the crop window to be clipped to 6x9 pixels, got %dx%d", crop.Width, crop.Height)
+		return
+	}
+	for y := 0; y < crop.Height; y++ {
+		for x := 0; x < crop.Width; x++ {
+			testVectorEquals(t, crop.Pixels[y][x][:], full.Pixels[y+2][x+3][:])
+		}
+	}
+
+	c.SetCropWindow(0, 0, 0, 0)
+	image := c.Render(w)
+	if image.Width != 11 || image.Height != 11 {
+		t.Errorf("Expected an empty crop window to render the whole image")
+	}
+}
+
+func TestRenderer_SetCheckpoint(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c, err := NewCamera(11, 11, math.Pi/2,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	c.SetTileSize(4)
+	c.SetWorkers(1)
+	c.SetCropWindow(1, 1, 9, 9)
+	full := c.Render(w)
+
+	path := filepath.Join(t.TempDir(), "image.ppm.tiles")
+	c.SetCheckpoint(path)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.SetProgress(func(p Progress) {
+		cancel()
+	})
+	_, err = c.RenderContext(ctx, w)
+	var interrupted RenderInterrupted
+	if !errors.As(err, &interrupted) || interrupted.TilesDone == 0 {
+		t.Errorf("Expected the first render to be interrupted after some tiles, got: %v", err)
+		return
+	}
+
+	// a write cut short by the interruption is dropped
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	f.Write([]byte{1, 2, 3})
+	f.Close()
+
+	tiles := 0
+	c.SetProgress(func(p Progress) {
+		tiles++
+	})
+	resumed, err := c.RenderContext(context.Background(), w)
+	if err != nil {
+		t.Errorf("Expected the resumed render to finish, got: %s", err)
+		return
+	}
+	if tiles != 9-interrupted.TilesDone {
+		t.Errorf("Expected %d tiles to be rendered after resuming, got %d", 9-interrupted.TilesDone, tiles)
+	}
+	for y := 0; y < full.Height; y++ {
+		for x := 0; x < full.Width; x++ {
+			testVectorEquals(t, resumed.Pixels[y][x][:], full.Pixels[y][x][:])
+		}
+	}
+
+	// every tile is saved, a render with another crop window starts over
+	tiles = 0
+	c.Render(w)
+	if tiles != 0 {
+		t.Errorf("Expected every tile to be read from the checkpoint, %d were rendered", tiles)
+	}
+	c.SetCropWindow(0, 0, 11, 11)
+	c.Render(w)
+	if tiles != 9 {
+		t.Errorf("Expected a checkpoint of another crop window to be ignored, %d tiles were rendered", tiles)
+	}
+}
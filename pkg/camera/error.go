@@ -0,0 +1,22 @@
+package camera
+
+import (
+	"fmt"
+)
+
+//RenderInterrupted is the error returned when a render is stopped by its context before every tile was rendered.
+// Err holds the reason given by the context (context.Canceled or context.DeadlineExceeded)
+type RenderInterrupted struct {
+	TilesDone  int
+	TilesTotal int
+	Err        error
+}
+
+func (e RenderInterrupted) Error() string {
+	return fmt.Sprintf("Render interrupted after %d of %d tiles: %s", e.TilesDone, e.TilesTotal, e.Err)
+}
+
+//Unwrap returns the context error that interrupted the render
+func (e RenderInterrupted) Unwrap() error {
+	return e.Err
+}
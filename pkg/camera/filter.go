@@ -0,0 +1,117 @@
+package camera
+
+import (
+	"math"
+)
+
+//Filter is a pixel reconstruction filter, it weights each sample of a pixel by its offset (dx, dy) in pixels from
+// the pixel centre. Samples are only taken within Radius of the centre
+type Filter interface {
+	Radius() float64
+	Weight(dx, dy float64) float64
+}
+
+//BoxFilter weights every sample within its radius equally
+type BoxFilter struct {
+	radius float64
+}
+
+//NewBoxFilter returns a box filter of the given radius in pixels, a radius of 0.5 covers exactly one pixel
+func NewBoxFilter(radius float64) *BoxFilter {
+	return &BoxFilter{radius: radius}
+}
+
+//Radius returns the extent of the filter in pixels
+func (f *BoxFilter) Radius() float64 {
+	return f.radius
+}
+
+//Weight returns the weight of a sample at offset (dx, dy) from the pixel centre
+func (f *BoxFilter) Weight(dx, dy float64) float64 {
+	if math.Abs(dx) > f.radius || math.Abs(dy) > f.radius {
+		return 0
+	}
+	return 1
+}
+
+//TentFilter weights samples linearly down to zero at its radius
+type TentFilter struct {
+	radius float64
+}
+
+//NewTentFilter returns a tent (triangle) filter of the given radius in pixels
+func NewTentFilter(radius float64) *TentFilter {
+	return &TentFilter{radius: radius}
+}
+
+//Radius returns the extent of the filter in pixels
+func (f *TentFilter) Radius() float64 {
+	return f.radius
+}
+
+//Weight returns the weight of a sample at offset (dx, dy) from the pixel centre
+func (f *TentFilter) Weight(dx, dy float64) float64 {
+	return math.Max(0, f.radius-math.Abs(dx)) * math.Max(0, f.radius-math.Abs(dy))
+}
+
+//GaussianFilter weights samples with a gaussian curve that is shifted to reach zero at its radius
+type GaussianFilter struct {
+	radius float64
+	alpha  float64 // falloff rate of the gaussian
+	edge   float64 // value of the gaussian at the radius
+}
+
+//NewGaussianFilter returns a gaussian filter of the given radius in pixels and falloff alpha
+func NewGaussianFilter(radius, alpha float64) *GaussianFilter {
+	return &GaussianFilter{radius: radius, alpha: alpha, edge: math.Exp(-alpha * radius * radius)}
+}
+
+//Radius returns the extent of the filter in pixels
+func (f *GaussianFilter) Radius() float64 {
+	return f.radius
+}
+
+//Weight returns the weight of a sample at offset (dx, dy) from the pixel centre
+func (f *GaussianFilter) Weight(dx, dy float64) float64 {
+	return f.gaussian(dx) * f.gaussian(dy)
+}
+
+func (f *GaussianFilter) gaussian(d float64) float64 {
+	return math.Max(0, math.Exp(-f.alpha*d*d)-f.edge)
+}
+
+//MitchellFilter is the Mitchell-Netravali cubic filter, its negative lobes sharpen edges that the other
+// filters blur
+type MitchellFilter struct {
+	radius float64
+	b, c   float64
+}
+
+//NewMitchellFilter returns a Mitchell-Netravali filter of the given radius in pixels, b = c = 1/3 are the
+// recommended parameters
+func NewMitchellFilter(radius, b, c float64) *MitchellFilter {
+	return &MitchellFilter{radius: radius, b: b, c: c}
+}
+
+//Radius returns the extent of the filter in pixels
+func (f *MitchellFilter) Radius() float64 {
+	return f.radius
+}
+
+//Weight returns the weight of a sample at offset (dx, dy) from the pixel centre
+func (f *MitchellFilter) Weight(dx, dy float64) float64 {
+	return f.mitchell(dx/f.radius) * f.mitchell(dy/f.radius)
+}
+
+//mitchell evaluates the 1D filter at x in [-1, 1], which is scaled to the [-2, 2] support of the cubic
+func (f *MitchellFilter) mitchell(x float64) float64 {
+	x = math.Abs(2 * x)
+	b, c := f.b, f.c
+	if x >= 2 {
+		return 0
+	}
+	if x > 1 {
+		return ((-b-6*c)*x*x*x + (6*b+30*c)*x*x + (-12*b-48*c)*x + (8*b + 24*c)) / 6
+	}
+	return ((12-9*b-6*c)*x*x*x + (-18+12*b+6*c)*x*x + (6 - 2*b)) / 6
+}
@@ -0,0 +1,45 @@
+package camera
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoxFilter(t *testing.T) {
+	f := NewBoxFilter(0.5)
+	assertEquals(t, f.Radius(), 0.5)
+	assertEquals(t, f.Weight(0, 0), 1)
+	assertEquals(t, f.Weight(0.4, -0.5), 1)
+	assertEquals(t, f.Weight(0.6, 0), 0)
+}
+
+func TestTentFilter(t *testing.T) {
+	f := NewTentFilter(1)
+	assertEquals(t, f.Weight(0, 0), 1)
+	assertEquals(t, f.Weight(0.5, 0), 0.5)
+	assertEquals(t, f.Weight(0.5, 0.5), 0.25)
+	assertEquals(t, f.Weight(1, 0), 0)
+}
+
+func TestGaussianFilter(t *testing.T) {
+	f := NewGaussianFilter(1.5, 2)
+	if !equals(f.Weight(0, 0), math.Pow(1-math.Exp(-2*1.5*1.5), 2)) {
+		t.Errorf("Incorrect gaussian weight at the centre %f", f.Weight(0, 0))
+	}
+	if f.Weight(0.5, 0) >= f.Weight(0, 0) {
+		t.Errorf("Expected gaussian weight to decrease away from the centre")
+	}
+	assertEquals(t, f.Weight(1.5, 0), 0)
+}
+
+func TestMitchellFilter(t *testing.T) {
+	f := NewMitchellFilter(2, 1.0/3, 1.0/3)
+	if !equals(f.Weight(0, 0), math.Pow(8.0/9, 2)) {
+		t.Errorf("Incorrect mitchell weight at the centre %f", f.Weight(0, 0))
+	}
+	if f.Weight(1.5, 0) >= 0 {
+		t.Errorf("Expected mitchell filter to have a negative lobe, got %f", f.Weight(1.5, 0))
+	}
+	assertEquals(t, f.Weight(2, 0), 0)
+	assertEquals(t, f.Weight(3, 0), 0)
+}
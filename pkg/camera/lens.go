@@ -0,0 +1,46 @@
+package camera
+
+import (
+	"math"
+	"math/rand"
+)
+
+//SetLens turns the camera into a thin lens camera with an aperture of the given radius, in world units. Points at
+// focalDistance from the camera are in focus and the rest of the scene is blurred the more the wider the
+// aperture is. An aperture of 0 is a pinhole camera where everything is in focus
+func (c *Camera) SetLens(aperture, focalDistance float64) {
+	c.aperture = aperture
+	c.focalDistance = focalDistance
+}
+
+//SetApertureBlades gives the aperture the shape of a regular polygon with the given number of blades, rotated by
+// rotation radians, which shapes out of focus highlights (bokeh). Fewer than 3 blades is a circular aperture
+func (c *Camera) SetApertureBlades(blades int, rotation float64) {
+	c.blades = blades
+	c.bladeRotation = rotation
+}
+
+//sampleLens returns a uniformly distributed point on the aperture in camera space, a nil rng returns its centre
+func (c Camera) sampleLens(rng *rand.Rand) (float64, float64) {
+	if rng == nil {
+		return 0, 0
+	}
+	if c.blades < 3 {
+		r := c.aperture * math.Sqrt(rng.Float64())
+		theta := 2 * math.Pi * rng.Float64()
+		return r * math.Cos(theta), r * math.Sin(theta)
+	}
+
+	// every triangle fanning out from the centre of a regular polygon has the same area, pick one then sample it
+	side := 2 * math.Pi / float64(c.blades)
+	i := rng.Intn(c.blades)
+	a := c.bladeRotation + float64(i)*side
+	b := a + side
+	u, v := rng.Float64(), rng.Float64()
+	if u+v > 1 {
+		u, v = 1-u, 1-v
+	}
+	x := c.aperture * (u*math.Cos(a) + v*math.Cos(b))
+	y := c.aperture * (u*math.Sin(a) + v*math.Sin(b))
+	return x, y
+}
@@ -0,0 +1,53 @@
+package camera
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"math"
+	"testing"
+)
+
+func TestCamera_sampleLens(t *testing.T) {
+	c := NewDefaultCamera(201, 101, math.Pi/2)
+	c.SetLens(0.5, 10)
+	x, y := c.sampleLens(nil)
+	assertEquals(t, x, 0)
+	assertEquals(t, y, 0)
+
+	rng := pixelRand(1, 2, 3)
+	for i := 0; i < 200; i++ {
+		x, y = c.sampleLens(rng)
+		if math.Hypot(x, y) > 0.5 {
+			t.Errorf("Expected lens sample (%f, %f) to be within the aperture", x, y)
+		}
+	}
+
+	// a square aperture with its corners on the axes
+	c.SetApertureBlades(4, 0)
+	for i := 0; i < 200; i++ {
+		x, y = c.sampleLens(rng)
+		if math.Abs(x)+math.Abs(y) > 0.5+algebra.EPSILON {
+			t.Errorf("Expected lens sample (%f, %f) to be within the square aperture", x, y)
+		}
+	}
+}
+
+func TestCamera_SetLens(t *testing.T) {
+	c := NewDefaultCamera(201, 101, math.Pi/2)
+	c.SetLens(0.5, 10)
+	r := c.RayForPixel(100, 50)
+	testVectorEquals(t, r.Get()["origin"].Get(), []float64{0, 0, 0, 1})
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 0, -1, 0})
+
+	// every ray through the pixel passes through the same point of the focal plane
+	rng := pixelRand(0, 0, 0)
+	for i := 0; i < 10; i++ {
+		r = c.rayForPoint(100.5, 50.5, rng)
+		origin := r.Get()["origin"]
+		direction := r.Get()["direction"]
+		if !equals(origin.Get()[2], 0) {
+			t.Errorf("Expected ray to start on the lens, got %v", origin.Get())
+		}
+		t10 := -10 / direction.Get()[2]
+		testVectorEquals(t, r.Position(t10).Get(), []float64{0, 0, -10, 1})
+	}
+}
@@ -0,0 +1,39 @@
+package camera
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+)
+
+//NewOrthographicCamera returns a new camera with a parallel projection of the given size in pixels. The camera
+// sees a viewWidth x viewHeight rectangle, in world units, centred on it and perpendicular to its view direction.
+// transform is the same 4x4 view transform used by NewCamera, it is typically built with algebra.ViewTransform
+func NewOrthographicCamera(hSize, vSize, viewWidth, viewHeight float64, transform *algebra.Matrix) (*Camera, error) {
+	if len(transform.Get()) != 4 || len(transform.Get()[0]) != 4 {
+		return nil, algebra.ExpectedDimension(4)
+	}
+	return &Camera{hSize: hSize, vSize: vSize,
+		halfWidth:    viewWidth / 2,
+		halfHeight:   viewHeight / 2,
+		pixelSize:    viewWidth / hSize,
+		transform:    transform,
+		Renderer:     *NewRenderer(),
+		orthographic: true}, nil
+}
+
+//orthographicRay returns the ray passing through the point (x, y) of the image plane, in pixel units. All rays of
+// an orthographic camera are parallel to its view direction and start on the plane of the camera, the lens
+// settings do not apply
+func (c Camera) orthographicRay(x, y float64) *algebra.Ray {
+	worldX := c.halfWidth - x*(2*c.halfWidth/c.hSize)
+	worldY := c.halfHeight - y*(2*c.halfHeight/c.vSize)
+
+	inverse := c.transform.Inverse()
+	origin := inverse.MultiplyByVec(algebra.NewPoint(worldX, worldY, 0))
+	direction, err := inverse.MultiplyByVec(algebra.NewVector(0, 0, -1)).Normalize()
+	if err != nil {
+		panic(err)
+	}
+	o := origin.Get()
+	d := direction.Get()
+	return algebra.NewRay(o[0], o[1], o[2], d[0], d[1], d[2])
+}
@@ -0,0 +1,63 @@
+package camera
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+	"testing"
+)
+
+func TestNewOrthographicCamera(t *testing.T) {
+	c, err := NewOrthographicCamera(200, 100, 4, 2, algebra.IdentityMatrix(4))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	assertEquals(t, c.halfWidth, 2)
+	assertEquals(t, c.halfHeight, 1)
+	assertEquals(t, c.pixelSize, 0.02)
+
+	_, err = NewOrthographicCamera(200, 100, 4, 2, algebra.IdentityMatrix(3))
+	if err == nil {
+		t.Errorf("Expected an error for a 3x3 transform")
+	}
+}
+
+func TestCamera_RayForPixelOrthographic(t *testing.T) {
+	c, err := NewOrthographicCamera(200, 100, 4, 2, algebra.IdentityMatrix(4))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	r := c.RayForPixel(99.5, 49.5)
+	testVectorEquals(t, r.Get()["origin"].Get(), []float64{0, 0, 0, 1})
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 0, -1, 0})
+
+	r = c.RayForPixel(0, 0)
+	testVectorEquals(t, r.Get()["origin"].Get(), []float64{1.99, 0.99, 0, 1})
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 0, -1, 0})
+
+	c.transform = algebra.Multiply(algebra.RotationY(math.Pi/4), algebra.TranslationMatrix(0, -2, 5))
+	r = c.RayForPixel(99.5, 49.5)
+	testVectorEquals(t, r.Get()["origin"].Get(), []float64{0, 2, -5})
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{math.Sqrt(2) / 2, 0, -math.Sqrt(2) / 2})
+}
+
+func TestCamera_RenderOrthographic(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c, err := NewOrthographicCamera(11, 11, 3, 3,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	image := c.Render(w)
+	// the centre ray hits the sphere head on like the perspective camera's
+	color := image.Pixels[5][5]
+	testVectorEquals(t, color[:], []float64{0.38066, 0.47583, 0.2855})
+	// the unit sphere covers 2 of the 3 world units seen, the corners miss it
+	testVectorEquals(t, image.Pixels[0][0][:], []float64{0, 0, 0})
+	if image.Pixels[5][2].Red() == 0 {
+		t.Errorf("Expected pixels within the sphere's radius to hit it")
+	}
+}
@@ -0,0 +1,92 @@
+package camera
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Progress describes how far along a render is, it is handed to the camera's ProgressFunc each time a tile is done
+type Progress struct {
+	PixelsDone  int
+	PixelsTotal int
+	Rays        int64         // number of rays cast from the camera so far
+	Refinement  int64         // number of those rays cast by adaptive supersampling to refine high contrast pixels
+	Elapsed     time.Duration // time since the render started
+	Remaining   time.Duration // estimate of the time left based on the average time per pixel so far
+}
+
+//ProgressFunc observes the progress of a render. Calls are serialized, so the function does not need to be safe
+// for concurrent use, but it is called from the render workers and should return quickly
+type ProgressFunc func(p Progress)
+
+//SetProgress sets the observer notified after every rendered tile, nil disables progress reporting
+func (r *Renderer) SetProgress(f ProgressFunc) {
+	r.progress = f
+}
+
+//Fraction returns the fraction of pixels rendered, between 0 and 1
+func (p Progress) Fraction() float64 {
+	if p.PixelsTotal == 0 {
+		return 1
+	}
+	return float64(p.PixelsDone) / float64(p.PixelsTotal)
+}
+
+//NewProgressBar returns a ProgressFunc that redraws a single line progress bar with the ETA on out
+func NewProgressBar(out io.Writer) ProgressFunc {
+	width := 40
+	return func(p Progress) {
+		filled := int(p.Fraction() * float64(width))
+		bar := strings.Repeat("=", filled) + strings.Repeat(" ", width-filled)
+		fmt.Fprintf(out, "\r[%s] %3.0f%% %d rays, elapsed %s, ETA %s ", bar, p.Fraction()*100, p.Rays,
+			p.Elapsed.Round(time.Second), p.Remaining.Round(time.Second))
+		if p.PixelsDone == p.PixelsTotal {
+			fmt.Fprintln(out)
+		}
+	}
+}
+
+//progressTracker accumulates the work done by the render workers and forwards it to a ProgressFunc
+type progressTracker struct {
+	mu       sync.Mutex
+	observer ProgressFunc
+	start    time.Time
+	total    int
+	pixels   int
+	rays     rayCount
+}
+
+func newProgressTracker(observer ProgressFunc, total int) *progressTracker {
+	return &progressTracker{observer: observer, start: time.Now(), total: total}
+}
+
+//tileDone records a finished tile of the given number of pixels that needed the given number of rays
+func (t *progressTracker) tileDone(pixels int, rays rayCount) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pixels += pixels
+	t.rays = t.rays.add(rays)
+	if t.observer == nil {
+		return
+	}
+	elapsed := time.Since(t.start)
+	var remaining time.Duration
+	if t.pixels > 0 {
+		remaining = time.Duration(float64(elapsed) * float64(t.total-t.pixels) / float64(t.pixels))
+	}
+	t.observer(Progress{PixelsDone: t.pixels, PixelsTotal: t.total, Rays: t.rays.rays,
+		Refinement: t.rays.refinement, Elapsed: elapsed, Remaining: remaining})
+}
+
+//rayCount tallies the camera rays traced for some pixels
+type rayCount struct {
+	rays       int64 // every camera ray traced
+	refinement int64 // rays traced by adaptive refinement on top of the initial pixel corners
+}
+
+func (r rayCount) add(r2 rayCount) rayCount {
+	return rayCount{rays: r.rays + r2.rays, refinement: r.refinement + r2.refinement}
+}
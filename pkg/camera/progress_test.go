@@ -0,0 +1,61 @@
+package camera
+
+import (
+	"bytes"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCamera_SetProgress(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c, err := NewCamera(10, 6, math.Pi/2,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	c.SetWorkers(3)
+	c.SetTileSize(4)
+	events := make([]Progress, 0, 0)
+	c.SetProgress(func(p Progress) {
+		events = append(events, p)
+	})
+	c.Render(w)
+
+	if len(events) != len(Tiles(10, 6, 4)) {
+		t.Errorf("Expected one progress event per tile, got %d", len(events))
+		return
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].PixelsDone <= events[i-1].PixelsDone || events[i].Rays <= events[i-1].Rays {
+			t.Errorf("Expected progress to increase between events")
+		}
+	}
+	last := events[len(events)-1]
+	if last.PixelsDone != 60 || last.PixelsTotal != 60 {
+		t.Errorf("Expected 60 of 60 pixels done, got %d of %d", last.PixelsDone, last.PixelsTotal)
+	}
+	if last.Rays != 60 {
+		t.Errorf("Expected 60 rays cast, got %d", last.Rays)
+	}
+	if last.Remaining != 0 {
+		t.Errorf("Expected no remaining time after the last tile, got %s", last.Remaining)
+	}
+	assertEquals(t, last.Fraction(), 1.0)
+}
+
+func TestNewProgressBar(t *testing.T) {
+	var out bytes.Buffer
+	bar := NewProgressBar(&out)
+	bar(Progress{PixelsDone: 25, PixelsTotal: 100, Rays: 25})
+	if !strings.Contains(out.String(), " 25%") {
+		t.Errorf("Expected progress bar to show 25%%, got %q", out.String())
+	}
+	bar(Progress{PixelsDone: 100, PixelsTotal: 100, Rays: 100})
+	if !strings.HasSuffix(out.String(), "\n") {
+		t.Errorf("Expected finished progress bar to end its line")
+	}
+}
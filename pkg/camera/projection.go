@@ -0,0 +1,138 @@
+package camera
+
+import (
+	"context"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+	"math/rand"
+)
+
+//Projection is implemented by every camera model, it maps the points of the image to the rays cast into the World.
+// A Renderer turns the rays of any Projection into an image
+type Projection interface {
+	//Size returns the width and height in pixels of the rendered image
+	Size() (int, int)
+	//RayAt returns the ray through the point (x, y) of the image, in pixel units from its top left corner.
+	// rng is used by models that sample their lens and may be nil. Points that see nothing return nil and are
+	// rendered black
+	RayAt(x, y float64, rng *rand.Rand) *algebra.Ray
+}
+
+//Equirectangular is a 360 x 180 degree panoramic camera. The columns of the image map linearly to the longitude
+// around the camera and its rows to the latitude, the centre of the image looks down the camera's view direction
+type Equirectangular struct {
+	width, height int
+	transform     *algebra.Matrix
+	Renderer
+}
+
+//NewEquirectangularCamera returns a panoramic camera rendering width x height images, usually with width = 2 * height.
+// transform is the view transform of the camera, typically built with algebra.ViewTransform
+func NewEquirectangularCamera(width, height int, transform *algebra.Matrix) (*Equirectangular, error) {
+	if len(transform.Get()) != 4 || len(transform.Get()[0]) != 4 {
+		return nil, algebra.ExpectedDimension(4)
+	}
+	return &Equirectangular{width: width, height: height, transform: transform, Renderer: *NewRenderer()}, nil
+}
+
+//Size returns the width and height in pixels of the images rendered by the camera
+func (e Equirectangular) Size() (int, int) {
+	return e.width, e.height
+}
+
+//RayAt returns the ray through the point (x, y) of the panorama
+func (e Equirectangular) RayAt(x, y float64, rng *rand.Rand) *algebra.Ray {
+	longitude := (x/float64(e.width) - 0.5) * 2 * math.Pi
+	latitude := (0.5 - y/float64(e.height)) * math.Pi
+	// the camera looks down -z and its right is -x, like the perspective Camera
+	dx := -math.Sin(longitude) * math.Cos(latitude)
+	dy := math.Sin(latitude)
+	dz := -math.Cos(longitude) * math.Cos(latitude)
+	return cameraSpaceRay(e.transform, dx, dy, dz)
+}
+
+//Render renders the World as a panorama around the camera
+func (e Equirectangular) Render(w *geometry.World) *canvas.Canvas {
+	image, err := e.RenderContext(context.Background(), w)
+	if err != nil {
+		panic(err)
+	}
+	return image
+}
+
+//RenderContext renders the World like Render but can be interrupted by ctx, see Renderer.RenderProjection
+func (e Equirectangular) RenderContext(ctx context.Context, w *geometry.World) (*canvas.Canvas, error) {
+	return e.RenderProjection(ctx, e, w)
+}
+
+//Fisheye is an angular (equidistant) fisheye camera. The distance of a pixel from the centre of the image is
+// proportional to the angle between its ray and the view direction, up to half the field of view at the edge of
+// the image circle. Pixels outside of the circle are black
+type Fisheye struct {
+	width, height int
+	fov           float64
+	transform     *algebra.Matrix
+	Renderer
+}
+
+//NewFisheyeCamera returns a fisheye camera rendering width x height images with the given field of view in radians,
+// math.Pi gives a hemispherical dome master. The image circle fits the smallest side of the image
+func NewFisheyeCamera(width, height int, fov float64, transform *algebra.Matrix) (*Fisheye, error) {
+	if len(transform.Get()) != 4 || len(transform.Get()[0]) != 4 {
+		return nil, algebra.ExpectedDimension(4)
+	}
+	return &Fisheye{width: width, height: height, fov: fov, transform: transform, Renderer: *NewRenderer()}, nil
+}
+
+//Size returns the width and height in pixels of the images rendered by the camera
+func (f Fisheye) Size() (int, int) {
+	return f.width, f.height
+}
+
+//RayAt returns the ray through the point (x, y) of the image, or nil outside of the image circle
+func (f Fisheye) RayAt(x, y float64, rng *rand.Rand) *algebra.Ray {
+	radius := math.Min(float64(f.width), float64(f.height)) / 2
+	nx := (x - float64(f.width)/2) / radius
+	ny := (float64(f.height)/2 - y) / radius
+	r := math.Hypot(nx, ny)
+	if r > 1 {
+		return nil
+	}
+	theta := r * f.fov / 2
+	phi := math.Atan2(ny, nx)
+	// the camera looks down -z and its right is -x, like the perspective Camera
+	dx := -math.Sin(theta) * math.Cos(phi)
+	dy := math.Sin(theta) * math.Sin(phi)
+	dz := -math.Cos(theta)
+	return cameraSpaceRay(f.transform, dx, dy, dz)
+}
+
+//Render renders the World through the fisheye lens
+func (f Fisheye) Render(w *geometry.World) *canvas.Canvas {
+	image, err := f.RenderContext(context.Background(), w)
+	if err != nil {
+		panic(err)
+	}
+	return image
+}
+
+//RenderContext renders the World like Render but can be interrupted by ctx, see Renderer.RenderProjection
+func (f Fisheye) RenderContext(ctx context.Context, w *geometry.World) (*canvas.Canvas, error) {
+	return f.RenderProjection(ctx, f, w)
+}
+
+//cameraSpaceRay returns the world space ray leaving a camera with the given view transform in the direction
+// (dx, dy, dz) of camera space
+func cameraSpaceRay(transform *algebra.Matrix, dx, dy, dz float64) *algebra.Ray {
+	inverse := transform.Inverse()
+	origin := inverse.MultiplyByVec(algebra.NewPoint(0, 0, 0))
+	direction, err := inverse.MultiplyByVec(algebra.NewVector(dx, dy, dz)).Normalize()
+	if err != nil {
+		panic(err)
+	}
+	o := origin.Get()
+	d := direction.Get()
+	return algebra.NewRay(o[0], o[1], o[2], d[0], d[1], d[2])
+}
@@ -0,0 +1,98 @@
+package camera
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+	"testing"
+)
+
+func TestCamera_Projection(t *testing.T) {
+	var p Projection = NewDefaultCamera(201, 101, math.Pi/2)
+	width, height := p.Size()
+	if width != 201 || height != 101 {
+		t.Errorf("Expected size 201x101, got %dx%d", width, height)
+	}
+	r := p.RayAt(100.5, 50.5, nil)
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 0, -1, 0})
+}
+
+func TestEquirectangular_RayAt(t *testing.T) {
+	e, err := NewEquirectangularCamera(360, 180, algebra.IdentityMatrix(4))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	r := e.RayAt(180, 90, nil)
+	testVectorEquals(t, r.Get()["origin"].Get(), []float64{0, 0, 0, 1})
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 0, -1, 0})
+	// a quarter turn to the right
+	r = e.RayAt(270, 90, nil)
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{-1, 0, 0, 0})
+	// straight behind at both edges of the image
+	r = e.RayAt(0, 90, nil)
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 0, 1, 0})
+	// straight up at the top of the image
+	r = e.RayAt(123, 0, nil)
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 1, 0, 0})
+
+	e.transform = algebra.Multiply(algebra.RotationY(math.Pi/4), algebra.TranslationMatrix(0, -2, 5))
+	r = e.RayAt(180, 90, nil)
+	testVectorEquals(t, r.Get()["origin"].Get(), []float64{0, 2, -5})
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{math.Sqrt(2) / 2, 0, -math.Sqrt(2) / 2})
+
+	_, err = NewEquirectangularCamera(360, 180, algebra.IdentityMatrix(3))
+	if err == nil {
+		t.Errorf("Expected an error for a 3x3 transform")
+	}
+}
+
+func TestFisheye_RayAt(t *testing.T) {
+	f, err := NewFisheyeCamera(200, 100, math.Pi, algebra.IdentityMatrix(4))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	r := f.RayAt(100, 50, nil)
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 0, -1, 0})
+	// the edge of the image circle is 90 degrees away from the view direction
+	r = f.RayAt(100, 0, nil)
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 1, 0, 0})
+	r = f.RayAt(150, 50, nil)
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{-1, 0, 0, 0})
+	// halfway to the edge is 45 degrees away
+	r = f.RayAt(125, 50, nil)
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{-math.Sqrt(2) / 2, 0, -math.Sqrt(2) / 2, 0})
+	if f.RayAt(10, 10, nil) != nil {
+		t.Errorf("Expected no ray outside of the image circle")
+	}
+}
+
+func TestEquirectangular_Render(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	e, err := NewEquirectangularCamera(40, 20,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	image := e.Render(w)
+	if image.Width != 40 || image.Height != 20 {
+		t.Errorf("Expected a 40x20 panorama, got %dx%d", image.Width, image.Height)
+	}
+	if image.Pixels[10][20].Red() == 0 {
+		t.Errorf("Expected the centre of the panorama to see the spheres")
+	}
+	testVectorEquals(t, image.Pixels[10][0][:], []float64{0, 0, 0})
+
+	f, err := NewFisheyeCamera(20, 20, math.Pi,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	image = f.Render(w)
+	if image.Pixels[10][10].Red() == 0 {
+		t.Errorf("Expected the centre of the fisheye to see the spheres")
+	}
+}
@@ -0,0 +1,250 @@
+package camera
+
+import (
+	"context"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+//Renderer holds the settings used to turn the rays of a Projection into an image: how the work is split between
+// goroutines, how pixels are sampled and who is told about the progress. Every camera model embeds one
+type Renderer struct {
+	workers  int // number of goroutines rendering tiles concurrently
+	tileSize int // width and height in pixels of a render tile
+	progress ProgressFunc
+	sampler  *Sampler // nil traces a single ray through each pixel centre
+	adaptive *AdaptiveSampler
+
+	integrator geometry.Integrator // nil uses World.ColorAt up to RECURSIONDEPTH bounces
+
+	shutterOpen  float64 // interval of time camera rays are cast in, for motion blur
+	shutterClose float64
+
+	crop       *Tile  // part of the image to render, nil renders all of it
+	checkpoint string // path of the state file finished tiles are saved to, empty for none
+}
+
+//NewRenderer returns a Renderer with one worker per CPU, tiles of DEFAULTTILESIZE pixels and one ray per pixel
+func NewRenderer() *Renderer {
+	return &Renderer{workers: runtime.NumCPU(), tileSize: DEFAULTTILESIZE}
+}
+
+//Tile describes a rectangular block of pixels that is rendered as a single unit of work
+type Tile struct {
+	X, Y          int // top left pixel of the tile
+	Width, Height int
+}
+
+//Tiles splits a width x height image into tiles of at most size x size pixels, ordered row by row
+func Tiles(width, height, size int) []Tile {
+	if size < 1 {
+		size = DEFAULTTILESIZE
+	}
+	tiles := make([]Tile, 0, 0)
+	for y := 0; y < height; y += size {
+		for x := 0; x < width; x += size {
+			tiles = append(tiles, Tile{X: x, Y: y,
+				Width:  minInt(size, width-x),
+				Height: minInt(size, height-y)})
+		}
+	}
+	return tiles
+}
+
+//SetWorkers sets the number of goroutines used to render tiles, values below 1 render on a single goroutine
+func (r *Renderer) SetWorkers(n int) {
+	r.workers = n
+}
+
+//SetCropWindow restricts rendering to the width x height rectangle of the image whose top left pixel is (x, y).
+// The rendered canvas then only holds that rectangle. The window is clipped to the image, a width or height below 1
+// renders the whole image
+func (r *Renderer) SetCropWindow(x, y, width, height int) {
+	if width < 1 || height < 1 {
+		r.crop = nil
+		return
+	}
+	r.crop = &Tile{X: x, Y: y, Width: width, Height: height}
+}
+
+//cropWindow returns the part of a width x height image to render
+func (r Renderer) cropWindow(width, height int) Tile {
+	if r.crop == nil {
+		return Tile{Width: width, Height: height}
+	}
+	x0, y0 := maxInt(r.crop.X, 0), maxInt(r.crop.Y, 0)
+	x1, y1 := minInt(r.crop.X+r.crop.Width, width), minInt(r.crop.Y+r.crop.Height, height)
+	return Tile{X: x0, Y: y0, Width: maxInt(x1-x0, 0), Height: maxInt(y1-y0, 0)}
+}
+
+//SetTileSize sets the width and height in pixels of the tiles handed to the workers
+func (r *Renderer) SetTileSize(size int) {
+	if size < 1 {
+		size = DEFAULTTILESIZE
+	}
+	r.tileSize = size
+}
+
+//SetIntegrator sets how the color seen along each camera ray is computed, such as a geometry.PathTracer. Each pixel
+// hands its own random numbers to the integrator so renders stay reproducible. nil restores the default
+// geometry.WhittedIntegrator
+func (r *Renderer) SetIntegrator(i geometry.Integrator) {
+	r.integrator = i
+}
+
+//RenderProjection renders the World as seen through the Projection p, or the part of it inside the crop window.
+// It stops handing out tiles once ctx is cancelled or its deadline passes, in that case the partially rendered
+// canvas is returned along with a RenderInterrupted error and the tiles that were not rendered are left black.
+// With a checkpoint, the tiles saved by a previous run are reused and the others are saved as they finish
+func (r Renderer) RenderProjection(ctx context.Context, p Projection, w *geometry.World) (*canvas.Canvas, error) {
+	width, height := p.Size()
+	crop := r.cropWindow(width, height)
+	image := canvas.NewCanvas(crop.Width, crop.Height)
+	tiles := Tiles(crop.Width, crop.Height, r.tileSize)
+	for i := range tiles {
+		tiles[i].X += crop.X
+		tiles[i].Y += crop.Y
+	}
+
+	todo := tiles
+	var state *checkpoint
+	if r.checkpoint != "" {
+		var err error
+		state, err = openCheckpoint(r.checkpoint, newCheckpointHeader(width, height, crop, r.tileSize))
+		if err != nil {
+			return image, err
+		}
+		todo = state.restore(image, crop, tiles)
+	}
+	done := len(tiles) - len(todo) + r.renderTiles(ctx, p, w, image, crop, todo, state)
+	err := state.close()
+	if done < len(tiles) {
+		return image, RenderInterrupted{TilesDone: done, TilesTotal: len(tiles), Err: ctx.Err()}
+	}
+	return image, err
+}
+
+//renderTiles renders the given tiles into image, whose top left pixel is the top left corner of crop, using a pool
+// of workers and returns how many tiles were finished. Every pixel is computed independently from the others so the
+// result does not depend on the number of workers or on the order tiles are picked up in. The context is checked
+// before each tile is started and finished tiles are saved to the checkpoint state when there is one
+func (r Renderer) renderTiles(ctx context.Context, p Projection, w *geometry.World, image *canvas.Canvas, crop Tile,
+	tiles []Tile, state *checkpoint) int {
+	workers := r.workers
+	if workers < 1 {
+		workers = 1
+	}
+	pixels := 0
+	for _, t := range tiles {
+		pixels += t.Width * t.Height
+	}
+	tracker := newProgressTracker(r.progress, pixels)
+	jobs := make(chan Tile)
+	var done int64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				rays := r.renderTile(p, w, image, crop, t)
+				state.save(image, crop, t)
+				atomic.AddInt64(&done, 1)
+				tracker.tileDone(t.Width*t.Height, rays)
+			}
+		}()
+	}
+dispatch:
+	for _, t := range tiles {
+		select {
+		case jobs <- t:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return int(done)
+}
+
+//renderTile renders every pixel of a single tile and returns the number of camera rays it cast,
+// tiles never overlap so workers write to disjoint pixels. Adaptive supersampling, which shares the corners of
+// neighbouring pixels, works on the whole tile and takes precedence over the Sampler when both are set
+func (r Renderer) renderTile(p Projection, w *geometry.World, image *canvas.Canvas, crop Tile, t Tile) rayCount {
+	if r.adaptive != nil {
+		colors, rays := r.adaptive.sampleTile(t, r.seed(), func(px, py float64, rng *rand.Rand) *canvas.Color {
+			return r.trace(p, w, px, py, rng)
+		})
+		for j, row := range colors {
+			for i, color := range row {
+				image.WritePixel(t.X+i-crop.X, t.Y+j-crop.Y, color)
+			}
+		}
+		return rays
+	}
+	var rays rayCount
+	for y := t.Y; y < t.Y+t.Height; y++ {
+		for x := t.X; x < t.X+t.Width; x++ {
+			color, n := r.renderPixel(p, w, x, y)
+			image.WritePixel(x-crop.X, y-crop.Y, color)
+			rays = rays.add(n)
+		}
+	}
+	return rays
+}
+
+//renderPixel returns the color of the pixel (x, y) and the number of camera rays traced to compute it
+func (r Renderer) renderPixel(p Projection, w *geometry.World, x, y int) (*canvas.Color, rayCount) {
+	rng := pixelRand(r.seed(), x, y)
+	trace := func(px, py float64) *canvas.Color {
+		return r.trace(p, w, px, py, rng)
+	}
+	if r.sampler != nil {
+		color, rays := r.sampler.samplePixel(x, y, rng, trace)
+		return color, rayCount{rays: rays}
+	}
+	return trace(float64(x)+0.5, float64(y)+0.5), rayCount{rays: 1}
+}
+
+//trace returns the color seen through the point (px, py) of the image plane, using rng for the random numbers of the
+// lens, the shutter and the integrator
+func (r Renderer) trace(p Projection, w *geometry.World, px, py float64, rng *rand.Rand) *canvas.Color {
+	ray := p.RayAt(px, py, rng)
+	if ray == nil {
+		return &canvas.Color{0, 0, 0}
+	}
+	ray.SetTime(r.sampleTime(rng))
+	if r.integrator != nil {
+		return r.integrator.Li(w, ray, rng)
+	}
+	return w.ColorAt(ray, RECURSIONDEPTH)
+}
+
+//seed returns the seed of the per pixel random numbers, taken from the Sampler when there is one
+func (r Renderer) seed() int64 {
+	if r.sampler != nil {
+		return r.sampler.Seed
+	}
+	return 0
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
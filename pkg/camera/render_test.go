@@ -0,0 +1,124 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTiles(t *testing.T) {
+	tiles := Tiles(10, 7, 4)
+	if len(tiles) != 6 {
+		t.Errorf("Expected %d tiles, got %d", 6, len(tiles))
+		return
+	}
+	expected := []Tile{
+		{0, 0, 4, 4}, {4, 0, 4, 4}, {8, 0, 2, 4},
+		{0, 4, 4, 3}, {4, 4, 4, 3}, {8, 4, 2, 3},
+	}
+	for i, tile := range tiles {
+		if tile != expected[i] {
+			t.Errorf("Expected tile %v, got %v", expected[i], tile)
+		}
+	}
+
+	covered := 0
+	for _, tile := range Tiles(37, 23, 5) {
+		covered += tile.Width * tile.Height
+	}
+	if covered != 37*23 {
+		t.Errorf("Expected tiles to cover %d pixels, got %d", 37*23, covered)
+	}
+}
+
+func TestCamera_RenderParallel(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c, err := NewCamera(21, 17, math.Pi/2,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	c.SetWorkers(1)
+	c.SetTileSize(100)
+	serial := c.Render(w)
+
+	c.SetWorkers(4)
+	c.SetTileSize(3)
+	parallel := c.Render(w)
+
+	for y := 0; y < serial.Height; y++ {
+		for x := 0; x < serial.Width; x++ {
+			if *serial.Pixels[y][x] != *parallel.Pixels[y][x] {
+				t.Errorf("Expected pixel (%d, %d) to be %v, got %v", x, y, *serial.Pixels[y][x], *parallel.Pixels[y][x])
+			}
+		}
+	}
+}
+
+func TestCamera_RenderContext(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c, err := NewCamera(11, 11, math.Pi/2,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	c.SetTileSize(4)
+	image, err := c.RenderContext(context.Background(), w)
+	if err != nil {
+		t.Errorf("Expected render to finish, got: %s", err)
+	}
+	if !equals(image.Pixels[5][5].Red(), 0.38066) {
+		t.Errorf("Incorrect red color %f, wanted %f", image.Pixels[5][5].Red(), 0.38066)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	image, err = c.RenderContext(ctx, w)
+	if image == nil || image.Width != 11 || image.Height != 11 {
+		t.Errorf("Expected a partial 11x11 canvas to be returned")
+	}
+	var interrupted RenderInterrupted
+	if !errors.As(err, &interrupted) {
+		t.Errorf("Expected a RenderInterrupted error, got: %v", err)
+		return
+	}
+	if interrupted.TilesTotal != 9 || interrupted.TilesDone != 0 {
+		t.Errorf("Expected 0 of 9 tiles to be done, got %d of %d", interrupted.TilesDone, interrupted.TilesTotal)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error to wrap context.Canceled")
+	}
+}
+
+//constantIntegrator sees the same color along every ray
+type constantIntegrator struct {
+	color canvas.Color
+}
+
+func (i constantIntegrator) Li(w *geometry.World, r *algebra.Ray, rng *rand.Rand) *canvas.Color {
+	c := i.color
+	return &c
+}
+
+func TestRenderer_SetIntegrator(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c := NewDefaultCamera(5, 5, math.Pi/2)
+	c.SetIntegrator(constantIntegrator{color: canvas.Color{0.25, 0.5, 0.75}})
+	image := c.Render(w)
+	if *image.Pixels[2][2] != (canvas.Color{0.25, 0.5, 0.75}) {
+		t.Errorf("Expected the color of the integrator, got %v", image.Pixels[2][2])
+	}
+
+	c.SetIntegrator(nil)
+	expected := w.ColorAt(c.RayForPixel(2, 2), RECURSIONDEPTH)
+	if got := c.Render(w).Pixels[2][2]; *got != *expected {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
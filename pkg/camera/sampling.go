@@ -0,0 +1,127 @@
+package camera
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"math"
+	"math/rand"
+)
+
+//SamplingStrategy decides where the samples of a pixel are placed
+type SamplingStrategy int
+
+const (
+	//RegularSampling places the samples at the centres of the cells of a regular grid
+	RegularSampling SamplingStrategy = iota
+	//JitteredSampling places one random sample in each cell of a regular grid
+	JitteredSampling
+	//RandomSampling places the samples uniformly at random
+	RandomSampling
+)
+
+//Sampler describes how many rays are traced per pixel, where they are placed and how they are combined
+type Sampler struct {
+	Samples  int // samples per pixel, rounded to the closest square for regular and jittered sampling
+	Strategy SamplingStrategy
+	Filter   Filter // reconstruction filter, samples are spread over the filter's radius around the pixel centre
+	Seed     int64  // seed of the random placement, the same seed always renders the same image
+}
+
+//NewSampler returns a new Sampler, a nil filter defaults to a box filter covering the pixel
+func NewSampler(samples int, strategy SamplingStrategy, filter Filter, seed int64) *Sampler {
+	if filter == nil {
+		filter = NewBoxFilter(0.5)
+	}
+	return &Sampler{Samples: samples, Strategy: strategy, Filter: filter, Seed: seed}
+}
+
+//SetSampler sets the supersampling settings, nil traces a single ray through each pixel centre
+func (r *Renderer) SetSampler(s *Sampler) {
+	r.sampler = s
+}
+
+//Positions returns the sample positions within the unit square for one pixel
+func (s *Sampler) Positions(rng *rand.Rand) [][2]float64 {
+	samples := s.Samples
+	if samples < 1 {
+		samples = 1
+	}
+	positions := make([][2]float64, 0, samples)
+	if s.Strategy == RandomSampling {
+		for i := 0; i < samples; i++ {
+			positions = append(positions, [2]float64{rng.Float64(), rng.Float64()})
+		}
+		return positions
+	}
+
+	n := int(math.Max(1, math.Round(math.Sqrt(float64(samples)))))
+	cell := 1.0 / float64(n)
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			dx, dy := 0.5, 0.5
+			if s.Strategy == JitteredSampling {
+				dx, dy = rng.Float64(), rng.Float64()
+			}
+			positions = append(positions, [2]float64{(float64(i) + dx) * cell, (float64(j) + dy) * cell})
+		}
+	}
+	return positions
+}
+
+//samplePixel traces the samples of pixel (x, y) and combines them with the reconstruction filter. The samples are
+// placed with rng and trace returns the color seen through a point of the image plane in pixel units.
+// The number of samples traced is also returned
+func (s *Sampler) samplePixel(x, y int, rng *rand.Rand, trace func(px, py float64) *canvas.Color) (*canvas.Color, int64) {
+	radius := math.Max(s.Filter.Radius(), 0.5)
+	cx, cy := float64(x)+0.5, float64(y)+0.5
+
+	weighted := &canvas.Color{0, 0, 0}
+	average := &canvas.Color{0, 0, 0}
+	totalWeight := 0.0
+	positions := s.Positions(rng)
+	for _, p := range positions {
+		dx := (2*p[0] - 1) * radius
+		dy := (2*p[1] - 1) * radius
+		color := trace(cx+dx, cy+dy)
+		weight := s.Filter.Weight(dx, dy)
+		weighted = weighted.Add(color.ScalarMult(weight))
+		average = average.Add(color)
+		totalWeight += weight
+	}
+	// filters with negative lobes can cancel out, fall back to the plain average of the samples
+	if totalWeight < algebra.EPSILON {
+		return average.ScalarMult(1 / float64(len(positions))), int64(len(positions))
+	}
+	return weighted.ScalarMult(1 / totalWeight), int64(len(positions))
+}
+
+//pixelRand returns a random number generator that only depends on the seed and the pixel, so a render is
+// reproducible whatever order the workers render its pixels in
+func pixelRand(seed int64, x, y int) *rand.Rand {
+	state := uint64(seed) ^ (uint64(x)+1)*0x9E3779B97F4A7C15 ^ (uint64(y)+1)*0xC2B2AE3D27D4EB4F
+	return rand.New(&splitMix{state: state})
+}
+
+//splitMix is a small, fast rand.Source64 (SplitMix64), cheap enough to create one for every pixel
+type splitMix struct {
+	state uint64
+}
+
+//Seed resets the state of the source
+func (s *splitMix) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+//Uint64 returns the next pseudo-random 64 bit value
+func (s *splitMix) Uint64() uint64 {
+	s.state += 0x9E3779B97F4A7C15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
+
+//Int63 returns the next pseudo-random non-negative 63 bit value
+func (s *splitMix) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
@@ -0,0 +1,90 @@
+package camera
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+	"testing"
+)
+
+func TestSampler_Positions(t *testing.T) {
+	s := NewSampler(4, RegularSampling, nil, 0)
+	positions := s.Positions(pixelRand(0, 0, 0))
+	expected := [][2]float64{{0.25, 0.25}, {0.75, 0.25}, {0.25, 0.75}, {0.75, 0.75}}
+	if len(positions) != len(expected) {
+		t.Errorf("Expected %d positions, got %d", len(expected), len(positions))
+		return
+	}
+	for i, p := range positions {
+		testVectorEquals(t, p[:], expected[i][:])
+	}
+
+	s = NewSampler(9, JitteredSampling, nil, 3)
+	positions = s.Positions(pixelRand(3, 1, 2))
+	if len(positions) != 9 {
+		t.Errorf("Expected %d positions, got %d", 9, len(positions))
+	}
+	for i, p := range positions {
+		cellX, cellY := float64(i%3)/3, float64(i/3)/3
+		if p[0] < cellX || p[0] >= cellX+1.0/3 || p[1] < cellY || p[1] >= cellY+1.0/3 {
+			t.Errorf("Expected jittered sample %v to be in its grid cell", p)
+		}
+	}
+	again := s.Positions(pixelRand(3, 1, 2))
+	for i := range positions {
+		if positions[i] != again[i] {
+			t.Errorf("Expected samples with the same seed to be identical")
+		}
+	}
+	other := s.Positions(pixelRand(4, 1, 2))
+	if other[0] == positions[0] {
+		t.Errorf("Expected samples with a different seed to differ")
+	}
+
+	s = NewSampler(5, RandomSampling, nil, 0)
+	if len(s.Positions(pixelRand(0, 0, 0))) != 5 {
+		t.Errorf("Expected random sampling to keep the exact number of samples")
+	}
+}
+
+func TestCamera_SetSampler(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c, err := NewCamera(11, 11, math.Pi/2,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	single := c.Render(w)
+
+	c.SetSampler(NewSampler(1, RegularSampling, NewBoxFilter(0.5), 0))
+	centre := c.Render(w)
+	for y := 0; y < single.Height; y++ {
+		for x := 0; x < single.Width; x++ {
+			testVectorEquals(t, centre.Pixels[y][x][:], single.Pixels[y][x][:])
+		}
+	}
+
+	var rays int64
+	c.SetSampler(NewSampler(16, JitteredSampling, NewMitchellFilter(1, 1.0/3, 1.0/3), 7))
+	c.SetProgress(func(p Progress) {
+		rays = p.Rays
+	})
+	first := c.Render(w)
+	if rays != 16*11*11 {
+		t.Errorf("Expected %d rays to be cast, got %d", 16*11*11, rays)
+	}
+	c.SetWorkers(1)
+	second := c.Render(w)
+	for y := 0; y < first.Height; y++ {
+		for x := 0; x < first.Width; x++ {
+			if *first.Pixels[y][x] != *second.Pixels[y][x] {
+				t.Errorf("Expected seeded renders to be reproducible at pixel (%d, %d)", x, y)
+			}
+		}
+	}
+	// the centre of the sphere is smooth enough that supersampling barely changes it
+	if math.Abs(first.Pixels[5][5].Red()-0.38066) > 0.01 {
+		t.Errorf("Expected supersampled red %f to be close to %f", first.Pixels[5][5].Red(), 0.38066)
+	}
+}
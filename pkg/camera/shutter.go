@@ -0,0 +1,21 @@
+package camera
+
+import (
+	"math/rand"
+)
+
+//SetShutter sets the interval of time the shutter stays open for, in the time units of the shapes' primitives.Motion.
+// Every camera ray is cast at a random instant of the interval so moving shapes are blurred along their motion,
+// pair it with a Sampler so each pixel averages several instants. An empty interval renders every ray at open
+func (r *Renderer) SetShutter(open, close float64) {
+	r.shutterOpen = open
+	r.shutterClose = close
+}
+
+//sampleTime returns a uniformly distributed instant of the shutter interval, a nil rng returns the instant it opens
+func (r Renderer) sampleTime(rng *rand.Rand) float64 {
+	if rng == nil || r.shutterClose <= r.shutterOpen {
+		return r.shutterOpen
+	}
+	return r.shutterOpen + rng.Float64()*(r.shutterClose-r.shutterOpen)
+}
@@ -0,0 +1,53 @@
+package camera
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry/primitives"
+	"math"
+	"testing"
+)
+
+func TestRenderer_sampleTime(t *testing.T) {
+	r := NewRenderer()
+	rng := pixelRand(0, 1, 2)
+	assertEquals(t, r.sampleTime(rng), 0)
+
+	r.SetShutter(0.5, 1.5)
+	assertEquals(t, r.sampleTime(nil), 0.5)
+	for i := 0; i < 100; i++ {
+		time := r.sampleTime(rng)
+		if time < 0.5 || time > 1.5 {
+			t.Errorf("Expected time %f to be within the shutter interval", time)
+		}
+	}
+}
+
+func TestCamera_SetShutter(t *testing.T) {
+	s := primitives.NewSphere(nil)
+	s.SetMotion(primitives.NewMotion(algebra.TranslationMatrix(0, 0, 0), algebra.TranslationMatrix(6, 0, 0)))
+	light := canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-10, 10, -10))
+	w := &geometry.World{Objects: []primitives.Shape{s}, Lights: []canvas.Light{light}}
+	c, err := NewCamera(11, 11, math.Pi/2,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	c.SetSampler(NewSampler(16, JitteredSampling, nil, 3))
+	still := c.Render(w)
+
+	c.SetShutter(0, 1)
+	blurred := c.Render(w)
+	if blurred.Pixels[5][5].Red() >= still.Pixels[5][5].Red() || blurred.Pixels[5][5].Red() <= 0 {
+		t.Errorf("Expected the centre of the moving sphere to be blurred, got %f (still %f)",
+			blurred.Pixels[5][5].Red(), still.Pixels[5][5].Red())
+	}
+	again := c.Render(w)
+	for y := 0; y < blurred.Height; y++ {
+		for x := 0; x < blurred.Width; x++ {
+			testVectorEquals(t, again.Pixels[y][x][:], blurred.Pixels[y][x][:])
+		}
+	}
+}
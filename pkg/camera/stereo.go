@@ -0,0 +1,131 @@
+package camera
+
+import (
+	"context"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+)
+
+//Convergence decides how the two eyes of a StereoRig converge on the zero parallax plane
+type Convergence int
+
+const (
+	//OffAxis keeps the eyes parallel and shifts their image planes, which gives no vertical parallax
+	OffAxis Convergence = iota
+	//ToeIn rotates the eyes towards the convergence point
+	ToeIn
+)
+
+//StereoLayout decides how the two eye views are composited into a single image
+type StereoLayout int
+
+const (
+	//SideBySide puts the left eye on the left half of the image and the right eye on the right half
+	SideBySide StereoLayout = iota
+	//TopBottom puts the left eye on the top half of the image and the right eye on the bottom half
+	TopBottom
+	//Anaglyph takes the red channel from the left eye and the green and blue channels from the right eye,
+	// to be seen with red/cyan glasses
+	Anaglyph
+)
+
+//StereoRig renders a scene from two eyes on either side of a perspective Camera
+type StereoRig struct {
+	camera      *Camera
+	ipd         float64 // inter-pupillary distance, in world units
+	convergence float64 // distance from the camera to the zero parallax plane
+	mode        Convergence
+}
+
+//NewStereoRig returns a stereo rig centred on the camera c, with eyes ipd apart that converge at the given distance
+func NewStereoRig(c *Camera, ipd, convergence float64, mode Convergence) *StereoRig {
+	return &StereoRig{camera: c, ipd: ipd, convergence: convergence, mode: mode}
+}
+
+//Eyes returns the left and right eye cameras of the rig, they share the render settings of the rig's camera
+func (s *StereoRig) Eyes() (*Camera, *Camera) {
+	return s.eye(1), s.eye(-1)
+}
+
+//eye returns the camera of the eye on the given side, the camera's +x axis points left so the left eye is side 1
+func (s *StereoRig) eye(side float64) *Camera {
+	eye := *s.camera
+	half := side * s.ipd / 2
+	transform := algebra.Multiply(algebra.TranslationMatrix(-half, 0, 0), s.camera.transform)
+	switch s.mode {
+	case ToeIn:
+		angle := -math.Atan2(half, s.convergence)
+		transform = algebra.Multiply(algebra.RotationY(angle), transform)
+	default:
+		// the convergence point is seen half / convergence away from the centre of the eye's image plane
+		eye.shift = -half / s.convergence
+	}
+	eye.transform = transform
+	// both eyes render the same tiles, keep their checkpoints apart
+	if eye.checkpoint != "" {
+		if side > 0 {
+			eye.checkpoint += ".left"
+		} else {
+			eye.checkpoint += ".right"
+		}
+	}
+	return &eye
+}
+
+//Render renders the left and right eye views of the World and composites them with the given layout
+func (s *StereoRig) Render(w *geometry.World, layout StereoLayout) *canvas.Canvas {
+	image, err := s.RenderContext(context.Background(), w, layout)
+	if err != nil {
+		panic(err)
+	}
+	return image
+}
+
+//RenderContext renders the World like Render but can be interrupted by ctx, see Renderer.RenderProjection. The
+// partial views are still composited when the render is interrupted
+func (s *StereoRig) RenderContext(ctx context.Context, w *geometry.World, layout StereoLayout) (*canvas.Canvas, error) {
+	left, right := s.Eyes()
+	leftImage, err := left.RenderContext(ctx, w)
+	if err != nil {
+		width, height := right.Size()
+		return Composite(leftImage, canvas.NewCanvas(width, height), layout), err
+	}
+	rightImage, err := right.RenderContext(ctx, w)
+	return Composite(leftImage, rightImage, layout), err
+}
+
+//Composite combines the left and right eye views, which have the same size, into a single image
+func Composite(left, right *canvas.Canvas, layout StereoLayout) *canvas.Canvas {
+	switch layout {
+	case TopBottom:
+		image := canvas.NewCanvas(left.Width, 2*left.Height)
+		for y := 0; y < left.Height; y++ {
+			for x := 0; x < left.Width; x++ {
+				image.WritePixel(x, y, left.Pixels[y][x])
+				image.WritePixel(x, y+left.Height, right.Pixels[y][x])
+			}
+		}
+		return image
+	case Anaglyph:
+		image := canvas.NewCanvas(left.Width, left.Height)
+		for y := 0; y < left.Height; y++ {
+			for x := 0; x < left.Width; x++ {
+				l := left.Pixels[y][x]
+				r := right.Pixels[y][x]
+				image.WritePixel(x, y, &canvas.Color{l.Red(), r.Green(), r.Blue()})
+			}
+		}
+		return image
+	default:
+		image := canvas.NewCanvas(2*left.Width, left.Height)
+		for y := 0; y < left.Height; y++ {
+			for x := 0; x < left.Width; x++ {
+				image.WritePixel(x, y, left.Pixels[y][x])
+				image.WritePixel(x+left.Width, y, right.Pixels[y][x])
+			}
+		}
+		return image
+	}
+}
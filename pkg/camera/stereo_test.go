@@ -0,0 +1,76 @@
+package camera
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+	"testing"
+)
+
+func TestStereoRig_Eyes(t *testing.T) {
+	for _, mode := range []Convergence{OffAxis, ToeIn} {
+		c := NewDefaultCamera(201, 101, math.Pi/2)
+		rig := NewStereoRig(c, 0.2, 4, mode)
+		left, right := rig.Eyes()
+
+		r := left.RayForPixel(100, 50)
+		testVectorEquals(t, r.Get()["origin"].Get(), []float64{0.1, 0, 0, 1})
+		// the centre of each eye looks at the convergence point
+		testVectorEquals(t, r.Position(math.Hypot(0.1, 4)).Get(), []float64{0, 0, -4, 1})
+
+		r = right.RayForPixel(100, 50)
+		testVectorEquals(t, r.Get()["origin"].Get(), []float64{-0.1, 0, 0, 1})
+		testVectorEquals(t, r.Position(math.Hypot(0.1, 4)).Get(), []float64{0, 0, -4, 1})
+	}
+
+	// off-axis eyes stay parallel to the camera, their image planes are shifted instead
+	c := NewDefaultCamera(201, 101, math.Pi/2)
+	left, _ := NewStereoRig(c, 0.2, 4, OffAxis).Eyes()
+	testMatrixEquals(t, left.transform.Get(), algebra.TranslationMatrix(-0.1, 0, 0).Get())
+	assertEquals(t, left.shift, -0.025)
+	if c.shift != 0 {
+		t.Errorf("Expected the rig to leave its camera untouched")
+	}
+}
+
+func TestComposite(t *testing.T) {
+	left := canvas.NewCanvas(2, 1)
+	right := canvas.NewCanvas(2, 1)
+	left.WritePixel(0, 0, &canvas.Color{1, 0.5, 0.5})
+	right.WritePixel(0, 0, &canvas.Color{0.2, 0.4, 0.6})
+
+	image := Composite(left, right, SideBySide)
+	if image.Width != 4 || image.Height != 1 {
+		t.Errorf("Expected a 4x1 side by side image, got %dx%d", image.Width, image.Height)
+	}
+	testVectorEquals(t, image.Pixels[0][0][:], []float64{1, 0.5, 0.5})
+	testVectorEquals(t, image.Pixels[0][2][:], []float64{0.2, 0.4, 0.6})
+
+	image = Composite(left, right, TopBottom)
+	if image.Width != 2 || image.Height != 2 {
+		t.Errorf("Expected a 2x2 top bottom image, got %dx%d", image.Width, image.Height)
+	}
+	testVectorEquals(t, image.Pixels[1][0][:], []float64{0.2, 0.4, 0.6})
+
+	image = Composite(left, right, Anaglyph)
+	testVectorEquals(t, image.Pixels[0][0][:], []float64{1, 0.4, 0.6})
+}
+
+func TestStereoRig_Render(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c, err := NewCamera(11, 11, math.Pi/2,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	rig := NewStereoRig(c, 0.5, 5, OffAxis)
+	image := rig.Render(w, SideBySide)
+	if image.Width != 22 || image.Height != 11 {
+		t.Errorf("Expected a 22x11 side by side image, got %dx%d", image.Width, image.Height)
+	}
+	if *image.Pixels[5][5] == *image.Pixels[5][16] {
+		t.Errorf("Expected the eyes to see the sphere from different points of view")
+	}
+}
@@ -0,0 +1,181 @@
+package canvas
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"math"
+)
+
+//RectangleLight is a rectangular area Light split into uSteps x vSteps cells, each sampled once. Area lights are
+// sampled at several points of their surface and the fraction of those points visible from an illuminated point gives
+// soft shadows with penumbrae
+type RectangleLight struct {
+	Intensity *Color
+	Corner    *algebra.Vector // corner of the rectangle
+	UVec      *algebra.Vector // edge of a cell along the first side of the rectangle
+	VVec      *algebra.Vector // edge of a cell along the second side of the rectangle
+	USteps    int
+	VSteps    int
+	Jitter    bool    // sample a random point of each cell instead of its centre
+	Falloff   Falloff // NoFalloff unless set
+}
+
+//NewRectangleLight returns a new RectangleLight spanning the full edges uVec and vVec from corner, with the given
+// number of cells along each edge
+func NewRectangleLight(intensity *Color, corner, uVec *algebra.Vector, uSteps int, vVec *algebra.Vector, vSteps int, jitter bool) *RectangleLight {
+	if uSteps < 1 {
+		uSteps = 1
+	}
+	if vSteps < 1 {
+		vSteps = 1
+	}
+	return &RectangleLight{Intensity: intensity, Corner: corner,
+		UVec: uVec.MultScalar(1 / float64(uSteps)), VVec: vVec.MultScalar(1 / float64(vSteps)),
+		USteps: uSteps, VSteps: vSteps, Jitter: jitter}
+}
+
+//GetIntensity returns the color of the light
+func (l *RectangleLight) GetIntensity() *Color {
+	return l.Intensity
+}
+
+//IntensityAt returns the color of the light
+func (l *RectangleLight) IntensityAt(p *algebra.Vector) *Color {
+	return l.Intensity
+}
+
+//GetFalloff returns how the light decreases with the distance to it
+func (l *RectangleLight) GetFalloff() Falloff {
+	return l.Falloff
+}
+
+//DirectionFrom returns the unit vector pointing from p to the sample of the light and the distance between them
+func (l *RectangleLight) DirectionFrom(p, sample *algebra.Vector) (*algebra.Vector, float64) {
+	return directionTo(p, sample)
+}
+
+//ShadowRay returns the ray cast from p towards the sample of the light
+func (l *RectangleLight) ShadowRay(p, sample *algebra.Vector) (*algebra.Ray, float64) {
+	return shadowRay(l, p, sample)
+}
+
+//PointOnLight returns the sampled point of the cell (u, v), the jitter of a cell depends on the illuminated point
+// p so renders are reproducible
+func (l *RectangleLight) PointOnLight(u, v int, p *algebra.Vector) *algebra.Vector {
+	du, dv := 0.5, 0.5
+	if l.Jitter {
+		du, dv = Jitter(p, u, v)
+	}
+	point, err := l.Corner.Add(l.UVec.MultScalar(float64(u) + du))
+	if err != nil {
+		panic(err)
+	}
+	point, err = point.Add(l.VVec.MultScalar(float64(v) + dv))
+	if err != nil {
+		panic(err)
+	}
+	return point
+}
+
+//Samples returns a point of every cell of the rectangle
+func (l *RectangleLight) Samples(p *algebra.Vector) []*algebra.Vector {
+	samples := make([]*algebra.Vector, 0, l.USteps*l.VSteps)
+	for v := 0; v < l.VSteps; v++ {
+		for u := 0; u < l.USteps; u++ {
+			samples = append(samples, l.PointOnLight(u, v, p))
+		}
+	}
+	return samples
+}
+
+//SphereLight is a spherical area Light. Seen from an illuminated point the sphere is a disc, which is split into
+// NumSamples rings and sectors, sampled once each
+type SphereLight struct {
+	Intensity  *Color
+	Center     *algebra.Vector
+	Radius     float64
+	NumSamples int     // number of samples, rounded to the closest square
+	Jitter     bool    // sample a random point of each cell of the disc instead of its centre
+	Falloff    Falloff // NoFalloff unless set
+}
+
+//NewSphereLight returns a new SphereLight centred on center, sampled with the given number of samples
+func NewSphereLight(intensity *Color, center *algebra.Vector, radius float64, samples int, jitter bool) *SphereLight {
+	return &SphereLight{Intensity: intensity, Center: center, Radius: radius, NumSamples: samples, Jitter: jitter}
+}
+
+//GetIntensity returns the color of the light
+func (l *SphereLight) GetIntensity() *Color {
+	return l.Intensity
+}
+
+//IntensityAt returns the color of the light
+func (l *SphereLight) IntensityAt(p *algebra.Vector) *Color {
+	return l.Intensity
+}
+
+//GetFalloff returns how the light decreases with the distance to it
+func (l *SphereLight) GetFalloff() Falloff {
+	return l.Falloff
+}
+
+//DirectionFrom returns the unit vector pointing from p to the sample of the light and the distance between them
+func (l *SphereLight) DirectionFrom(p, sample *algebra.Vector) (*algebra.Vector, float64) {
+	return directionTo(p, sample)
+}
+
+//ShadowRay returns the ray cast from p towards the sample of the light
+func (l *SphereLight) ShadowRay(p, sample *algebra.Vector) (*algebra.Ray, float64) {
+	return shadowRay(l, p, sample)
+}
+
+//Samples returns points spread evenly over the disc of the sphere facing p
+func (l *SphereLight) Samples(p *algebra.Vector) []*algebra.Vector {
+	n := int(math.Max(1, math.Round(math.Sqrt(float64(l.NumSamples)))))
+	toPoint, err := p.Subtract(l.Center)
+	if err != nil {
+		panic(err)
+	}
+	u, v := algebra.OrthonormalBasis(toPoint)
+	samples := make([]*algebra.Vector, 0, n*n)
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			di, dj := 0.5, 0.5
+			if l.Jitter {
+				di, dj = Jitter(p, i, j)
+			}
+			// equal area cells: the squared radius and the angle are split evenly
+			r := l.Radius * math.Sqrt((float64(j)+dj)/float64(n))
+			theta := 2 * math.Pi * (float64(i) + di) / float64(n)
+			sample, err := l.Center.Add(u.MultScalar(r * math.Cos(theta)))
+			if err != nil {
+				panic(err)
+			}
+			sample, err = sample.Add(v.MultScalar(r * math.Sin(theta)))
+			if err != nil {
+				panic(err)
+			}
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+//Jitter returns a pseudo-random offset within the cell (i, j) that only depends on the illuminated point p and the
+// cell, so lights can be sampled from several goroutines and the same scene always renders the same image
+func Jitter(p *algebra.Vector, i, j int) (float64, float64) {
+	h := uint64(i+1)*0x9E3779B97F4A7C15 ^ uint64(j+1)*0xC2B2AE3D27D4EB4F
+	for _, c := range p.Get()[:3] {
+		h = mix(h ^ math.Float64bits(c))
+	}
+	a := mix(h)
+	b := mix(a)
+	return float64(a>>11) / (1 << 53), float64(b>>11) / (1 << 53)
+}
+
+//mix is the SplitMix64 finalizer
+func mix(z uint64) uint64 {
+	z += 0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
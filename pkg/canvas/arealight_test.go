@@ -0,0 +1,82 @@
+package canvas
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"math"
+	"testing"
+)
+
+func testNear(t *testing.T, got, expected []float64) {
+	for i := range expected {
+		if math.Abs(got[i]-expected[i]) > 0.0001 {
+			t.Errorf("Expected %v, Got: %v", expected, got)
+			return
+		}
+	}
+}
+
+func TestNewRectangleLight(t *testing.T) {
+	l := NewRectangleLight(&Color{1, 1, 1}, algebra.NewPoint(0, 0, 0), algebra.NewVector(2, 0, 0), 4,
+		algebra.NewVector(0, 0, 1), 2, false)
+	testNear(t, l.UVec.Get(), []float64{0.5, 0, 0, 0})
+	testNear(t, l.VVec.Get(), []float64{0, 0, 0.5, 0})
+
+	p := algebra.NewPoint(0, 5, 0)
+	testNear(t, l.PointOnLight(0, 0, p).Get(), []float64{0.25, 0, 0.25, 1})
+	testNear(t, l.PointOnLight(1, 0, p).Get(), []float64{0.75, 0, 0.25, 1})
+	testNear(t, l.PointOnLight(2, 1, p).Get(), []float64{1.25, 0, 0.75, 1})
+	testNear(t, l.PointOnLight(3, 1, p).Get(), []float64{1.75, 0, 0.75, 1})
+	if len(l.Samples(p)) != 8 {
+		t.Errorf("Expected one sample per cell, got %d", len(l.Samples(p)))
+	}
+
+	l.Jitter = true
+	for v := 0; v < 2; v++ {
+		for u := 0; u < 4; u++ {
+			s := l.PointOnLight(u, v, p).Get()
+			if s[0] < 0.5*float64(u) || s[0] > 0.5*float64(u+1) || s[2] < 0.5*float64(v) || s[2] > 0.5*float64(v+1) {
+				t.Errorf("Expected jittered sample %v to be within cell (%d, %d)", s, u, v)
+			}
+		}
+	}
+	// the jitter only depends on the illuminated point
+	testNear(t, l.PointOnLight(1, 1, p).Get(), l.PointOnLight(1, 1, algebra.NewPoint(0, 5, 0)).Get())
+}
+
+func TestSphereLight_Samples(t *testing.T) {
+	l := NewSphereLight(&Color{1, 1, 1}, algebra.NewPoint(0, 10, 0), 2, 16, true)
+	samples := l.Samples(algebra.NewPoint(0, 0, 0))
+	if len(samples) != 16 {
+		t.Errorf("Expected 16 samples, got %d", len(samples))
+	}
+	for _, s := range samples {
+		// the disc faces the illuminated point
+		if math.Abs(s.Get()[1]-10) > 0.0001 || math.Hypot(s.Get()[0], s.Get()[2]) > 2 {
+			t.Errorf("Expected sample %v to be on the disc of the light", s.Get())
+		}
+	}
+}
+
+func TestLightingSamples(t *testing.T) {
+	m := NewDefaultMaterial()
+	p := algebra.NewPoint(0, 0, 0)
+	eye := algebra.NewVector(0, 0, -1)
+	normal := algebra.NewVector(0, 0, -1)
+	light := NewPointLight(&Color{1, 1, 1}, algebra.NewPoint(0, 0, -10))
+	samples := light.Samples(p)
+
+	testNear(t, LightingSamples(m, nil, light, samples, nil, p, eye, normal, &Color{1, 1, 1})[:], []float64{1.9, 1.9, 1.9})
+	testNear(t, LightingSamples(m, nil, light, samples, nil, p, eye, normal, &Color{0.5, 0.5, 0.5})[:], []float64{1, 1, 1})
+	testNear(t, LightingSamples(m, nil, light, samples, nil, p, eye, normal, &Color{0, 0, 0})[:], []float64{0.1, 0.1, 0.1})
+
+	// a tinted visibility only lets through part of the light
+	testNear(t, LightingSamples(m, nil, light, samples, nil, p, eye, normal, &Color{1, 0.5, 0})[:], []float64{1.9, 1, 0.1})
+
+	// a sample behind the surface only contributes to the average with nothing
+	samples = append(samples, algebra.NewPoint(0, 0, 10))
+	testNear(t, LightingSamples(m, nil, light, samples, nil, p, eye, normal, &Color{1, 1, 1})[:], []float64{1, 1, 1})
+
+	// weighted samples give their share of the light, in the color of their weight
+	testNear(t, LightingSamples(m, nil, light, samples, []*Color{{2, 1, 0}, {0, 0, 0}}, p, eye, normal,
+		&Color{1, 1, 1})[:], []float64{1.9, 1, 0.1})
+}
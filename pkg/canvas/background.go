@@ -0,0 +1,155 @@
+package canvas
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"math"
+)
+
+//Background is the light coming from far away around the scene, seen by the rays that miss every object of the World
+type Background interface {
+	//ColorAt returns the color seen looking in the direction
+	ColorAt(direction *algebra.Vector) *Color
+}
+
+//SampledBackground is a Background that can be sampled as a light, such as an EnvironmentMap
+type SampledBackground interface {
+	Background
+	//Sample returns a direction picked from the numbers u and v in [0, 1), the color seen in that direction and the
+	// probability density of picking it per unit solid angle, 0 when the background gives no light
+	Sample(u, v float64) (*algebra.Vector, *Color, float64)
+}
+
+//SolidBackground is the same color in every direction
+type SolidBackground struct {
+	Color *Color
+}
+
+//NewSolidBackground returns a new Background of the given color
+func NewSolidBackground(color *Color) *SolidBackground {
+	return &SolidBackground{Color: color}
+}
+
+//ColorAt returns the color of the background
+func (b *SolidBackground) ColorAt(direction *algebra.Vector) *Color {
+	return b.Color
+}
+
+//GradientBackground goes linearly from its Bottom color, looking straight down, to its Top color, looking straight up
+type GradientBackground struct {
+	Bottom *Color
+	Top    *Color
+}
+
+//NewGradientBackground returns a new vertical gradient from bottom to top
+func NewGradientBackground(bottom, top *Color) *GradientBackground {
+	return &GradientBackground{Bottom: bottom, Top: top}
+}
+
+//ColorAt returns the color of the gradient at the height of the direction
+func (b *GradientBackground) ColorAt(direction *algebra.Vector) *Color {
+	d := direction.Get()
+	length := math.Sqrt(d[0]*d[0] + d[1]*d[1] + d[2]*d[2])
+	t := 0.5
+	if length > 0 {
+		t = (d[1]/length + 1) / 2
+	}
+	return b.Bottom.ScalarMult(1 - t).Add(b.Top.ScalarMult(t))
+}
+
+//CubeFace is the index of a face of a CubeMap
+type CubeFace int
+
+const (
+	PositiveX CubeFace = iota
+	NegativeX
+	PositiveY
+	NegativeY
+	PositiveZ
+	NegativeZ
+)
+
+//cubeAxes are the forward, up and right vectors of a camera looking at each face, cameras looking down -z have
+// their right towards -x
+var cubeAxes = [6][3][3]float64{
+	PositiveX: {{1, 0, 0}, {0, 1, 0}, {0, 0, -1}},
+	NegativeX: {{-1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+	PositiveY: {{0, 1, 0}, {0, 0, 1}, {-1, 0, 0}},
+	NegativeY: {{0, -1, 0}, {0, 0, -1}, {-1, 0, 0}},
+	PositiveZ: {{0, 0, 1}, {0, 1, 0}, {1, 0, 0}},
+	NegativeZ: {{0, 0, -1}, {0, 1, 0}, {-1, 0, 0}},
+}
+
+//CubeMap is a Background made of six images, one per face of a cube around the scene indexed by CubeFace. Each face
+// is the image seen by a camera with a 90 degree field of view looking along its axis, the sides with their top up,
+// the top face with its bottom towards -z and the bottom face with its top towards -z
+type CubeMap struct {
+	Faces     [6]*Canvas
+	Intensity float64 // factor the colors of the images are multiplied by
+}
+
+//NewCubeMap returns a new CubeMap of the six faces, in the order of CubeFace
+func NewCubeMap(faces [6]*Canvas, intensity float64) *CubeMap {
+	return &CubeMap{Faces: faces, Intensity: intensity}
+}
+
+//LoadCubeMap returns a new CubeMap of the six images at the paths, in the order of CubeFace, read with LoadImage
+func LoadCubeMap(paths [6]string, intensity float64) (*CubeMap, error) {
+	var faces [6]*Canvas
+	for i, path := range paths {
+		image, err := LoadImage(path)
+		if err != nil {
+			return nil, err
+		}
+		faces[i] = image
+	}
+	return NewCubeMap(faces, intensity), nil
+}
+
+//ColorAt returns the color of the face seen in the direction, interpolated between its closest pixels
+func (c *CubeMap) ColorAt(direction *algebra.Vector) *Color {
+	d := direction.Get()
+	face := PositiveX
+	major := math.Abs(d[0])
+	if math.Abs(d[1]) > major {
+		face, major = PositiveY, math.Abs(d[1])
+	}
+	if math.Abs(d[2]) > major {
+		face, major = PositiveZ, math.Abs(d[2])
+	}
+	if d[face/2] < 0 {
+		face++
+	}
+	if major == 0 {
+		return &Color{0, 0, 0}
+	}
+	axes := cubeAxes[face]
+	up := (d[0]*axes[1][0] + d[1]*axes[1][1] + d[2]*axes[1][2]) / major
+	right := (d[0]*axes[2][0] + d[1]*axes[2][1] + d[2]*axes[2][2]) / major
+	image := c.Faces[face]
+	x := (right + 1) / 2 * float64(image.Width)
+	y := (1 - up) / 2 * float64(image.Height)
+	return bilinear(image, x, y, false).ScalarMult(c.Intensity)
+}
+
+//bilinear returns the color at the point (x, y) of the image, in pixels, interpolated between the centres of the
+// closest pixels. Points past the sides wrap around with wrap and are clamped to the edge otherwise, points past the
+// top and bottom are clamped
+func bilinear(image *Canvas, x, y float64, wrap bool) *Color {
+	x, y = x-0.5, y-0.5
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	pixel := func(x, y int) *Color {
+		if wrap {
+			x %= image.Width
+			if x < 0 {
+				x += image.Width
+			}
+		}
+		x = int(math.Max(0, math.Min(float64(x), float64(image.Width-1))))
+		y = int(math.Max(0, math.Min(float64(y), float64(image.Height-1))))
+		return image.Pixels[y][x]
+	}
+	top := pixel(int(x0), int(y0)).ScalarMult(1 - fx).Add(pixel(int(x0)+1, int(y0)).ScalarMult(fx))
+	bottom := pixel(int(x0), int(y0)+1).ScalarMult(1 - fx).Add(pixel(int(x0)+1, int(y0)+1).ScalarMult(fx))
+	return top.ScalarMult(1 - fy).Add(bottom.ScalarMult(fy))
+}
@@ -0,0 +1,59 @@
+package canvas
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"testing"
+)
+
+func TestSolidBackground_ColorAt(t *testing.T) {
+	b := NewSolidBackground(&Color{0.2, 0.4, 0.6})
+	testNear(t, b.ColorAt(algebra.NewVector(1, -2, 3))[:], []float64{0.2, 0.4, 0.6})
+}
+
+func TestGradientBackground_ColorAt(t *testing.T) {
+	b := NewGradientBackground(&Color{1, 0, 0}, &Color{0, 0, 1})
+	tests := []struct {
+		direction *algebra.Vector
+		expected  []float64
+	}{
+		{algebra.NewVector(0, -2, 0), []float64{1, 0, 0}},
+		{algebra.NewVector(0, 3, 0), []float64{0, 0, 1}},
+		{algebra.NewVector(1, 0, 1), []float64{0.5, 0, 0.5}},
+		{algebra.NewVector(0, 1, 0).MultScalar(0), []float64{0.5, 0, 0.5}},
+	}
+	for _, test := range tests {
+		testNear(t, b.ColorAt(test.direction)[:], test.expected)
+	}
+}
+
+func TestCubeMap_ColorAt(t *testing.T) {
+	var faces [6]*Canvas
+	for i := range faces {
+		faces[i] = NewCanvas(2, 2)
+		for y := 0; y < 2; y++ {
+			for x := 0; x < 2; x++ {
+				faces[i].Pixels[y][x] = &Color{float64(i), 0, 0}
+			}
+		}
+	}
+	c := NewCubeMap(faces, 2)
+	axes := []*algebra.Vector{algebra.NewVector(3, 1, -1), algebra.NewVector(-3, 1, -1),
+		algebra.NewVector(1, 3, -1), algebra.NewVector(1, -3, -1), algebra.NewVector(1, 1, 3),
+		algebra.NewVector(1, 1, -3)}
+	for i, d := range axes {
+		testNear(t, c.ColorAt(d)[:], []float64{float64(2 * i), 0, 0})
+	}
+
+	// the front face seen looking down -z has its right towards -x and its top up
+	front := NewCanvas(2, 2)
+	front.Pixels[0][0] = &Color{1, 0, 0}
+	front.Pixels[0][1] = &Color{0, 1, 0}
+	front.Pixels[1][0] = &Color{0, 0, 1}
+	front.Pixels[1][1] = &Color{1, 1, 1}
+	faces[NegativeZ] = front
+	c = NewCubeMap(faces, 1)
+	testNear(t, c.ColorAt(algebra.NewVector(0.5, 0.5, -1))[:], []float64{1, 0, 0})
+	testNear(t, c.ColorAt(algebra.NewVector(-0.5, 0.5, -1))[:], []float64{0, 1, 0})
+	testNear(t, c.ColorAt(algebra.NewVector(0.5, -0.5, -1))[:], []float64{0, 0, 1})
+	testNear(t, c.ColorAt(algebra.NewVector(-0.5, -0.5, -1))[:], []float64{1, 1, 1})
+}
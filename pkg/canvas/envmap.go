@@ -0,0 +1,130 @@
+package canvas
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"math"
+	"sort"
+)
+
+//EnvironmentMap is a SampledBackground made of an equirectangular image of the light coming from every direction
+// around the scene, such as a panorama loaded from an HDR file. The columns of the image map linearly to the longitude
+// and its rows to the latitude, its centre is the light coming from -z, the same mapping as the panoramas of the
+// equirectangular camera. It is sampled in proportion to the luminance of its pixels, so the bright sun of a sky is
+// found with few samples
+type EnvironmentMap struct {
+	Image     *Canvas
+	Intensity float64     // factor the colors of the image are multiplied by
+	rowCDF    []float64   // cumulated weights of the rows, divided by the total weight
+	columnCDF [][]float64 // cumulated weights of the pixels of each row, divided by the weight of the row
+	weights   [][]float64 // luminance of each pixel times the area of the sphere it covers
+	total     float64
+}
+
+//NewEnvironmentMap returns a new EnvironmentMap of the image, whose colors are multiplied by intensity
+func NewEnvironmentMap(image *Canvas, intensity float64) *EnvironmentMap {
+	e := &EnvironmentMap{Image: image, Intensity: intensity}
+	e.weights = make([][]float64, image.Height)
+	e.columnCDF = make([][]float64, image.Height)
+	e.rowCDF = make([]float64, image.Height)
+	rows := 0.0
+	for y := 0; y < image.Height; y++ {
+		// rows near the poles cover less of the sphere
+		sin := math.Sin(math.Pi * (float64(y) + 0.5) / float64(image.Height))
+		e.weights[y] = make([]float64, image.Width)
+		e.columnCDF[y] = make([]float64, image.Width)
+		row := 0.0
+		for x := 0; x < image.Width; x++ {
+			e.weights[y][x] = luminance(image.Pixels[y][x]) * sin
+			row += e.weights[y][x]
+			e.columnCDF[y][x] = row
+		}
+		for x := range e.columnCDF[y] {
+			if row > 0 {
+				e.columnCDF[y][x] /= row
+			}
+		}
+		rows += row
+		e.rowCDF[y] = rows
+	}
+	for y := range e.rowCDF {
+		if rows > 0 {
+			e.rowCDF[y] /= rows
+		}
+	}
+	e.total = rows
+	return e
+}
+
+//LoadEnvironmentMap returns a new EnvironmentMap of the HDR image at path, read with LoadImage
+func LoadEnvironmentMap(path string, intensity float64) (*EnvironmentMap, error) {
+	image, err := LoadImage(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewEnvironmentMap(image, intensity), nil
+}
+
+//ColorAt returns the light coming from the direction, interpolated between the closest pixels
+func (e *EnvironmentMap) ColorAt(direction *algebra.Vector) *Color {
+	x, y := e.imagePoint(direction)
+	return bilinear(e.Image, x, y, true).ScalarMult(e.Intensity)
+}
+
+//Sample returns a direction picked with a probability proportional to the luminance of the light coming from it,
+// from the numbers u and v in [0, 1). It also returns the light coming from the direction and the probability
+// density of picking it, per unit solid angle, which is 0 for a black image
+func (e *EnvironmentMap) Sample(u, v float64) (*algebra.Vector, *Color, float64) {
+	if e.total <= 0 {
+		return algebra.NewVector(0, 1, 0), &Color{0, 0, 0}, 0
+	}
+	y, fy := searchCDF(e.rowCDF, v)
+	x, fx := searchCDF(e.columnCDF[y], u)
+	px := (float64(x) + fx) / float64(e.Image.Width)
+	py := (float64(y) + fy) / float64(e.Image.Height)
+	longitude := (px - 0.5) * 2 * math.Pi
+	latitude := (0.5 - py) * math.Pi
+	direction := algebra.NewVector(-math.Sin(longitude)*math.Cos(latitude), math.Sin(latitude),
+		-math.Cos(longitude)*math.Cos(latitude))
+
+	// density over the image, which covers 2 pi^2 cos(latitude) of solid angle per unit area
+	density := e.weights[y][x] / e.total * float64(e.Image.Width*e.Image.Height)
+	cos := math.Cos(latitude)
+	if cos <= 0 {
+		return direction, &Color{0, 0, 0}, 0
+	}
+	return direction, e.ColorAt(direction), density / (2 * math.Pi * math.Pi * cos)
+}
+
+//imagePoint returns the coordinates in pixels of the point of the image seen in the direction
+func (e *EnvironmentMap) imagePoint(direction *algebra.Vector) (float64, float64) {
+	d := direction.Get()
+	longitude := math.Atan2(-d[0], -d[2])
+	latitude := math.Asin(math.Max(-1, math.Min(1, d[1]/math.Sqrt(d[0]*d[0]+d[1]*d[1]+d[2]*d[2]))))
+	return (longitude/(2*math.Pi) + 0.5) * float64(e.Image.Width), (0.5 - latitude/math.Pi) * float64(e.Image.Height)
+}
+
+//searchCDF returns the index of the interval of the cumulated distribution the number u falls in and where in the
+// interval it falls, from 0 to 1
+func searchCDF(cdf []float64, u float64) (int, float64) {
+	i := sort.SearchFloat64s(cdf, u)
+	if i >= len(cdf) {
+		i = len(cdf) - 1
+	}
+	// skip the empty intervals of black pixels
+	for i < len(cdf)-1 && cdf[i] <= u {
+		i++
+	}
+	low := 0.0
+	if i > 0 {
+		low = cdf[i-1]
+	}
+	if cdf[i] <= low {
+		return i, 0.5
+	}
+	return i, math.Max(0, math.Min((u-low)/(cdf[i]-low), 1))
+}
+
+//luminance returns the relative luminance of a linear color
+func luminance(c *Color) float64 {
+	return 0.2126*c[0] + 0.7152*c[1] + 0.0722*c[2]
+}
@@ -0,0 +1,72 @@
+package canvas
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEnvironmentMap_ColorAt(t *testing.T) {
+	image := NewCanvas(8, 4)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			image.Pixels[y][x] = &Color{0.5, 0.5, 0.5}
+		}
+	}
+	e := NewEnvironmentMap(image, 2)
+	testNear(t, e.ColorAt(algebra.NewVector(0.3, -0.2, 0.9))[:], []float64{1, 1, 1})
+
+	tests := []struct {
+		direction *algebra.Vector
+		x, y      float64
+	}{
+		{algebra.NewVector(0, 0, -1), 4, 2},
+		{algebra.NewVector(-1, 0, 0), 6, 2},
+		{algebra.NewVector(1, 0, 0), 2, 2},
+		{algebra.NewVector(0, 0, 2), 0, 2},
+		{algebra.NewVector(0, 1, 0), 0, 0},
+		{algebra.NewVector(0, -1, 0), 0, 4},
+	}
+	for _, test := range tests {
+		x, y := e.imagePoint(test.direction)
+		testNear(t, []float64{x, y}, []float64{test.x, test.y})
+	}
+}
+
+func TestEnvironmentMap_Sample(t *testing.T) {
+	image := NewCanvas(16, 8)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 16; x++ {
+			image.Pixels[y][x] = &Color{1, 1, 1}
+		}
+	}
+	e := NewEnvironmentMap(image, 1)
+	rng := rand.New(rand.NewSource(1))
+	// the light of a white environment integrates to the area of the sphere
+	sum := 0.0
+	n := 4000
+	for i := 0; i < n; i++ {
+		_, c, pdf := e.Sample(rng.Float64(), rng.Float64())
+		sum += c.Red() / pdf
+	}
+	if average := sum / float64(n); math.Abs(average-4*math.Pi) > 0.2 {
+		t.Errorf("Expected the samples to average %f, got %f", 4*math.Pi, average)
+	}
+
+	// only the bright pixel of a black image is sampled
+	black := NewCanvas(16, 8)
+	e = NewEnvironmentMap(black, 1)
+	if _, _, pdf := e.Sample(0.5, 0.5); pdf != 0 {
+		t.Errorf("Expected a black environment not to be sampled, got a density of %f", pdf)
+	}
+	black.Pixels[2][5] = &Color{10, 10, 10}
+	e = NewEnvironmentMap(black, 1)
+	for i := 0; i < 100; i++ {
+		d, _, pdf := e.Sample(rng.Float64(), rng.Float64())
+		x, y := e.imagePoint(d)
+		if int(x) != 5 || int(y) != 2 || pdf <= 0 {
+			t.Fatalf("Expected the bright pixel to be sampled, got (%f, %f)", x, y)
+		}
+	}
+}
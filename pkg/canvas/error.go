@@ -0,0 +1,15 @@
+package canvas
+
+import (
+	"fmt"
+)
+
+//InvalidImage is the error returned when an image file cannot be decoded
+type InvalidImage struct {
+	Format string
+	Reason string
+}
+
+func (e InvalidImage) Error() string {
+	return fmt.Sprintf("Invalid %s image: %s", e.Format, e.Reason)
+}
@@ -0,0 +1,54 @@
+package canvas
+
+import "math"
+
+//LUMENSPERWATT is the luminous efficacy used to convert lumens to watts, that of light at 555nm
+var LUMENSPERWATT = 683.0
+
+//Falloff describes how the light decreases with the distance d to the light: its intensity is divided by
+// Constant + Linear*d + Quadratic*d*d. The zero Falloff leaves the intensity constant at any distance
+type Falloff struct {
+	Constant  float64
+	Linear    float64
+	Quadratic float64
+}
+
+//NoFalloff keeps the intensity of a light constant at any distance
+var NoFalloff = Falloff{}
+
+//LinearFalloff divides the intensity of a light by the distance to it
+var LinearFalloff = Falloff{Linear: 1}
+
+//InverseSquareFalloff divides the intensity of a light by the squared distance to it, like real lights
+var InverseSquareFalloff = Falloff{Quadratic: 1}
+
+//NewFalloff returns a custom Falloff from its constant, linear and quadratic terms
+func NewFalloff(constant, linear, quadratic float64) Falloff {
+	return Falloff{Constant: constant, Linear: linear, Quadratic: quadratic}
+}
+
+//Attenuation returns the factor the intensity of a light is multiplied by at distance d from it. Lights at an
+// infinite distance and lights with NoFalloff are not attenuated
+func (f Falloff) Attenuation(d float64) float64 {
+	if f == NoFalloff || math.IsInf(d, 1) {
+		return 1
+	}
+	denominator := f.Constant + f.Linear*d + f.Quadratic*d*d
+	if denominator <= 0 {
+		return 1
+	}
+	return 1 / denominator
+}
+
+//WattsToIntensity returns the intensity of a light of the given color radiating watts evenly in every direction,
+// in watts per steradian. Paired with the InverseSquareFalloff and scenes modelled in metres it gives the irradiance
+// of the light in watts per square metre. The color is the tint of the light and should have a brightest channel of 1
+func WattsToIntensity(color *Color, watts float64) *Color {
+	return color.ScalarMult(watts / (4 * math.Pi))
+}
+
+//LumensToIntensity returns the intensity of a light of the given color with a luminous flux of lumens, such as the
+// lumens printed on a light bulb, converted to watts with LUMENSPERWATT
+func LumensToIntensity(color *Color, lumens float64) *Color {
+	return WattsToIntensity(color, lumens/LUMENSPERWATT)
+}
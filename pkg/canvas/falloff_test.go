@@ -0,0 +1,57 @@
+package canvas
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"math"
+	"testing"
+)
+
+func TestFalloff_Attenuation(t *testing.T) {
+	tests := []struct {
+		falloff  Falloff
+		distance float64
+		expected float64
+	}{
+		{NoFalloff, 10, 1},
+		{LinearFalloff, 4, 0.25},
+		{InverseSquareFalloff, 4, 0.0625},
+		{NewFalloff(1, 0.5, 0.25), 2, 1.0 / 3},
+		{InverseSquareFalloff, 0, 1},
+		{InverseSquareFalloff, math.Inf(1), 1},
+	}
+	for _, test := range tests {
+		if a := test.falloff.Attenuation(test.distance); !equals(a, test.expected) {
+			t.Errorf("Expected attenuation %f at distance %f, Got: %f", test.expected, test.distance, a)
+		}
+	}
+}
+
+func TestWattsToIntensity(t *testing.T) {
+	testNear(t, WattsToIntensity(&Color{1, 0.5, 0}, 4*math.Pi)[:], []float64{1, 0.5, 0})
+	testNear(t, LumensToIntensity(&Color{1, 1, 1}, 4*math.Pi*LUMENSPERWATT)[:], []float64{1, 1, 1})
+}
+
+func TestLightingFalloff(t *testing.T) {
+	m := NewDefaultMaterial()
+	m.Specular = 0
+	p := algebra.NewPoint(0, 0, 0)
+	eye := algebra.NewVector(0, 0, -1)
+	normal := algebra.NewVector(0, 0, -1)
+	light := NewPointLight(&Color{1, 1, 1}, algebra.NewPoint(0, 0, -2))
+
+	testNear(t, Lighting(m, nil, light, p, eye, normal, false)[:], []float64{1, 1, 1})
+
+	// the ambient term is attenuated like the rest of the light
+	light.Falloff = InverseSquareFalloff
+	testNear(t, Lighting(m, nil, light, p, eye, normal, false)[:], []float64{0.25, 0.25, 0.25})
+	light.Falloff = LinearFalloff
+	testNear(t, Lighting(m, nil, light, p, eye, normal, false)[:], []float64{0.5, 0.5, 0.5})
+
+	// a bright bulb far away gives the ambient light reaching the point, not the ambient light of its wattage
+	bulb := NewPointLight(WattsToIntensity(&Color{1, 1, 1}, 400*math.Pi), algebra.NewPoint(0, 0, -10))
+	bulb.Falloff = InverseSquareFalloff
+	testNear(t, Lighting(m, nil, bulb, p, eye, normal, true)[:], []float64{0.1, 0.1, 0.1})
+
+	sun := NewDirectionalLight(&Color{1, 1, 1}, algebra.NewVector(0, 0, 1))
+	testNear(t, Lighting(m, nil, sun, p, eye, normal, false)[:], []float64{1, 1, 1})
+}
@@ -0,0 +1,205 @@
+package canvas
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//LoadImage reads a high dynamic range image from a Radiance (.hdr or .pic) or PFM (.pfm) file, the format is picked
+// from the extension of the path
+func LoadImage(path string) (*Canvas, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".hdr" && ext != ".pic" && ext != ".pfm" {
+		return nil, InvalidImage{Format: ext, Reason: "unsupported file extension"}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if ext == ".pfm" {
+		return ReadPFM(file)
+	}
+	return ReadHDR(file)
+}
+
+//ReadHDR decodes a Radiance RGBE image, either flat or run length encoded, with its top row first
+func ReadHDR(r io.Reader) (*Canvas, error) {
+	br := bufio.NewReader(r)
+	line, err := br.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "#?") {
+		return nil, InvalidImage{Format: "Radiance HDR", Reason: "missing #? signature"}
+	}
+	for {
+		line, err = br.ReadString('\n')
+		if err != nil {
+			return nil, InvalidImage{Format: "Radiance HDR", Reason: "unterminated header"}
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "FORMAT=") && line != "FORMAT=32-bit_rle_rgbe" {
+			return nil, InvalidImage{Format: "Radiance HDR", Reason: "unsupported " + line}
+		}
+	}
+
+	line, err = br.ReadString('\n')
+	fields := strings.Fields(line)
+	if err != nil || len(fields) != 4 || (fields[0] != "-Y" && fields[0] != "+Y") || fields[2] != "+X" {
+		return nil, InvalidImage{Format: "Radiance HDR", Reason: "unsupported resolution " + strings.TrimSpace(line)}
+	}
+	height, errH := strconv.Atoi(fields[1])
+	width, errW := strconv.Atoi(fields[3])
+	if errH != nil || errW != nil || width < 1 || height < 1 {
+		return nil, InvalidImage{Format: "Radiance HDR", Reason: "invalid size " + strings.TrimSpace(line)}
+	}
+
+	image := NewCanvas(width, height)
+	scanline := make([]byte, 4*width)
+	for row := 0; row < height; row++ {
+		if err := readScanline(br, scanline, width); err != nil {
+			return nil, err
+		}
+		y := row
+		if fields[0] == "+Y" {
+			y = height - 1 - row
+		}
+		for x := 0; x < width; x++ {
+			image.Pixels[y][x] = rgbeToColor(scanline[4*x : 4*x+4])
+		}
+	}
+	return image, nil
+}
+
+//readScanline reads the RGBE bytes of a row of width pixels, run length encoded rows store each of the four
+// components separately as runs of a repeated byte or of literal bytes
+func readScanline(br *bufio.Reader, scanline []byte, width int) error {
+	header, err := br.Peek(4)
+	if err != nil {
+		return InvalidImage{Format: "Radiance HDR", Reason: "truncated pixel data"}
+	}
+	if width < 8 || width > 0x7fff || header[0] != 2 || header[1] != 2 || header[2]&0x80 != 0 {
+		if _, err := io.ReadFull(br, scanline); err != nil {
+			return InvalidImage{Format: "Radiance HDR", Reason: "truncated pixel data"}
+		}
+		return nil
+	}
+	if int(header[2])<<8|int(header[3]) != width {
+		return InvalidImage{Format: "Radiance HDR", Reason: "scanline width mismatch"}
+	}
+	if _, err := br.Discard(4); err != nil {
+		return err
+	}
+	for c := 0; c < 4; c++ {
+		for x := 0; x < width; {
+			count, err := br.ReadByte()
+			if err != nil {
+				return InvalidImage{Format: "Radiance HDR", Reason: "truncated pixel data"}
+			}
+			run := int(count)
+			repeat := run > 128
+			if repeat {
+				run -= 128
+			}
+			if run == 0 || x+run > width {
+				return InvalidImage{Format: "Radiance HDR", Reason: "bad run length"}
+			}
+			var value byte
+			for i := 0; i < run; i++ {
+				if !repeat || i == 0 {
+					if value, err = br.ReadByte(); err != nil {
+						return InvalidImage{Format: "Radiance HDR", Reason: "truncated pixel data"}
+					}
+				}
+				scanline[4*(x+i)+c] = value
+			}
+			x += run
+		}
+	}
+	return nil
+}
+
+//rgbeToColor returns the color of an RGBE pixel, whose components share the exponent stored in the fourth byte
+func rgbeToColor(rgbe []byte) *Color {
+	if rgbe[3] == 0 {
+		return &Color{0, 0, 0}
+	}
+	f := math.Ldexp(1, int(rgbe[3])-(128+8))
+	return &Color{float64(rgbe[0]) * f, float64(rgbe[1]) * f, float64(rgbe[2]) * f}
+}
+
+//ReadPFM decodes a Portable Float Map, in color (PF) or greyscale (Pf). Rows are stored bottom to top and a negative
+// scale in the header marks little endian floats
+func ReadPFM(r io.Reader) (*Canvas, error) {
+	br := bufio.NewReader(r)
+	tokens := make([]string, 0, 4)
+	for len(tokens) < 4 {
+		token, err := readToken(br)
+		if err != nil {
+			return nil, InvalidImage{Format: "PFM", Reason: "truncated header"}
+		}
+		tokens = append(tokens, token)
+	}
+	channels := 0
+	switch tokens[0] {
+	case "PF":
+		channels = 3
+	case "Pf":
+		channels = 1
+	default:
+		return nil, InvalidImage{Format: "PFM", Reason: "missing PF or Pf signature"}
+	}
+	width, errW := strconv.Atoi(tokens[1])
+	height, errH := strconv.Atoi(tokens[2])
+	scale, errS := strconv.ParseFloat(tokens[3], 64)
+	if errW != nil || errH != nil || errS != nil || width < 1 || height < 1 || scale == 0 {
+		return nil, InvalidImage{Format: "PFM", Reason: "invalid header " + strings.Join(tokens, " ")}
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if scale < 0 {
+		order = binary.LittleEndian
+	}
+
+	image := NewCanvas(width, height)
+	row := make([]float32, width*channels)
+	for i := 0; i < height; i++ {
+		if err := binary.Read(br, order, row); err != nil {
+			return nil, InvalidImage{Format: "PFM", Reason: "truncated pixel data"}
+		}
+		y := height - 1 - i
+		for x := 0; x < width; x++ {
+			if channels == 1 {
+				v := float64(row[x])
+				image.Pixels[y][x] = &Color{v, v, v}
+			} else {
+				image.Pixels[y][x] = &Color{float64(row[3*x]), float64(row[3*x+1]), float64(row[3*x+2])}
+			}
+		}
+	}
+	return image, nil
+}
+
+//readToken returns the next whitespace separated word, the single whitespace byte ending it is consumed
+func readToken(br *bufio.Reader) (string, error) {
+	var token []byte
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if b == ' ' || b == '\t' || b == '\n' || b == '\r' {
+			if len(token) > 0 {
+				return string(token), nil
+			}
+			continue
+		}
+		token = append(token, b)
+	}
+}
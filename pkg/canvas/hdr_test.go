@@ -0,0 +1,101 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadHDR(t *testing.T) {
+	// a flat 2x2 image, top row first
+	flat := []byte("#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n-Y 2 +X 2\n")
+	flat = append(flat, 128, 64, 0, 129, 0, 0, 0, 0, 128, 128, 128, 128, 255, 0, 0, 130)
+	image, err := ReadHDR(bytes.NewReader(flat))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	testNear(t, image.Pixels[0][0][:], []float64{1, 0.5, 0})
+	testNear(t, image.Pixels[0][1][:], []float64{0, 0, 0})
+	testNear(t, image.Pixels[1][0][:], []float64{0.5, 0.5, 0.5})
+	testNear(t, image.Pixels[1][1][:], []float64{255.0 / 64, 0, 0})
+
+	// a run length encoded row of 8 pixels, the red channel alternates between literal bytes and a run
+	rle := append([]byte("#?RGBE\n\n+Y 1 +X 8\n"), 2, 2, 0, 8)
+	rle = append(rle, 4, 128, 0, 128, 0, 128+4, 64) // red
+	rle = append(rle, 128+8, 0)                     // green
+	rle = append(rle, 128+8, 0)                     // blue
+	rle = append(rle, 128+8, 129)                   // exponent
+	image, err = ReadHDR(bytes.NewReader(rle))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	for x, red := range []float64{1, 0, 1, 0, 0.5, 0.5, 0.5, 0.5} {
+		testNear(t, image.Pixels[0][x][:], []float64{red, 0, 0})
+	}
+
+	var invalid InvalidImage
+	if _, err := ReadHDR(bytes.NewReader([]byte("P3\n"))); !errors.As(err, &invalid) {
+		t.Errorf("Expected an InvalidImage error, got %v", err)
+	}
+	if _, err := ReadHDR(bytes.NewReader(flat[:len(flat)-3])); !errors.As(err, &invalid) {
+		t.Errorf("Expected an InvalidImage error for truncated data, got %v", err)
+	}
+}
+
+func TestReadPFM(t *testing.T) {
+	// a 2x1 color image in little endian
+	var data bytes.Buffer
+	data.WriteString("PF\n2 1\n-1.0\n")
+	binary.Write(&data, binary.LittleEndian, []float32{1, 2, 3, 0.25, 0.5, 0.75})
+	image, err := ReadPFM(&data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	testNear(t, image.Pixels[0][0][:], []float64{1, 2, 3})
+	testNear(t, image.Pixels[0][1][:], []float64{0.25, 0.5, 0.75})
+
+	// a 1x2 greyscale image in big endian, bottom row first
+	data.Reset()
+	data.WriteString("Pf 1 2 1.0\n")
+	binary.Write(&data, binary.BigEndian, []float32{4, 8})
+	image, err = ReadPFM(&data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	testNear(t, image.Pixels[0][0][:], []float64{8, 8, 8})
+	testNear(t, image.Pixels[1][0][:], []float64{4, 4, 4})
+
+	var invalid InvalidImage
+	if _, err := ReadPFM(bytes.NewReader([]byte("PF\n2 1\n-1.0\n\x00"))); !errors.As(err, &invalid) {
+		t.Errorf("Expected an InvalidImage error for truncated data, got %v", err)
+	}
+}
+
+func TestLoadImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hdr")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer os.RemoveAll(dir)
+	var data bytes.Buffer
+	data.WriteString("Pf\n1 1\n-1\n")
+	binary.Write(&data, binary.LittleEndian, []float32{2})
+	path := filepath.Join(dir, "probe.PFM")
+	if err := ioutil.WriteFile(path, data.Bytes(), 0644); err != nil {
+		t.Fatalf("%s", err)
+	}
+	image, err := LoadImage(path)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	testNear(t, image.Pixels[0][0][:], []float64{2, 2, 2})
+
+	var invalid InvalidImage
+	if _, err := LoadImage(filepath.Join(dir, "probe.png")); !errors.As(err, &invalid) {
+		t.Errorf("Expected an InvalidImage error for an unsupported extension, got %v", err)
+	}
+}
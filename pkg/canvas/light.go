@@ -5,10 +5,50 @@ import (
 	"math"
 )
 
+//Light is a light source of the World. A light is sampled at one or more points to light a point of a surface, the
+// diffuse and specular terms are averaged over the samples and shadow rays are cast towards each sample
+type Light interface {
+	//GetIntensity returns the color of the light
+	GetIntensity() *Color
+	//IntensityAt returns the color of the light shining towards the point p, before the falloff with distance
+	IntensityAt(p *algebra.Vector) *Color
+	//GetFalloff returns how the light decreases with the distance to it
+	GetFalloff() Falloff
+	//Samples returns the points of the light sampled to light the point p. Lights at infinity return directions
+	// (vectors with a w of 0) instead of points
+	Samples(p *algebra.Vector) []*algebra.Vector
+	//DirectionFrom returns the unit vector pointing from the point p to the sample of the light and the distance
+	// between them, which is infinite for lights at infinity
+	DirectionFrom(p, sample *algebra.Vector) (*algebra.Vector, float64)
+	//ShadowRay returns the ray cast from the point p towards the sample of the light, objects hit by the ray closer
+	// than the returned distance block the light
+	ShadowRay(p, sample *algebra.Vector) (*algebra.Ray, float64)
+}
+
+//WeightedLight is a Light whose samples do not all give the same light, such as the glowing surface of a shape whose
+// samples each stand for the part of the surface around them. The light of a weighted light comes from the weights
+// of its samples, it gives no ambient term
+type WeightedLight interface {
+	Light
+	//WeightedSamples returns the points of the light sampled to light the point p, like Samples, along with the
+	// color the light of each sample is multiplied by
+	WeightedSamples(p *algebra.Vector) ([]*algebra.Vector, []*Color)
+}
+
+//LightSamples returns the samples of the light for the point p and their weights, which are nil, all samples giving
+// the same light, unless the light is a WeightedLight
+func LightSamples(light Light, p *algebra.Vector) ([]*algebra.Vector, []*Color) {
+	if weighted, ok := light.(WeightedLight); ok {
+		return weighted.WeightedSamples(p)
+	}
+	return light.Samples(p), nil
+}
+
 //PointLight defines a light without size described by an Intensity color and a Position vector(point)
 type PointLight struct {
 	Intensity *Color
 	Position  *algebra.Vector
+	Falloff   Falloff // NoFalloff unless set
 }
 
 //NewPointLight returns a new PointLight with attributes provided as parameters
@@ -16,47 +56,187 @@ func NewPointLight(color *Color, position *algebra.Vector) *PointLight {
 	return &PointLight{Intensity: color, Position: position}
 }
 
-//Lighting computes the lighting from the PointLight onto the Material at the illuminatedPoint with its normal Vector
-// from the point of view of the eye vector
-func Lighting(material *Material, patternColor *Color, light *PointLight, illuminatedPoint, eyeVector, normalVector *algebra.Vector, inShadow bool) *Color {
-	var color *Color
-	if patternColor != nil {
-		color = patternColor
-	} else {
-		color = material.Color
+//GetIntensity returns the color of the light
+func (l *PointLight) GetIntensity() *Color {
+	return l.Intensity
+}
+
+//IntensityAt returns the color of the light, a point light shines the same in every direction
+func (l *PointLight) IntensityAt(p *algebra.Vector) *Color {
+	return l.Intensity
+}
+
+//GetFalloff returns how the light decreases with the distance to it
+func (l *PointLight) GetFalloff() Falloff {
+	return l.Falloff
+}
+
+//Samples returns the position of the light
+func (l *PointLight) Samples(p *algebra.Vector) []*algebra.Vector {
+	return []*algebra.Vector{l.Position}
+}
+
+//DirectionFrom returns the unit vector pointing from p to the light and the distance between them
+func (l *PointLight) DirectionFrom(p, sample *algebra.Vector) (*algebra.Vector, float64) {
+	return directionTo(p, sample)
+}
+
+//ShadowRay returns the ray cast from p towards the light
+func (l *PointLight) ShadowRay(p, sample *algebra.Vector) (*algebra.Ray, float64) {
+	return shadowRay(l, p, sample)
+}
+
+//SpotLight is a PointLight that only shines within a cone around its Direction
+type SpotLight struct {
+	PointLight
+	Direction  *algebra.Vector // unit vector the light points to
+	InnerAngle float64         // half angle, in radians, of the fully lit cone
+	OuterAngle float64         // half angle, in radians, past which the light gives no light
+}
+
+//NewSpotLight returns a new spot light at position pointing to direction. Points within innerAngle of the direction
+// are fully lit, the light fades out smoothly up to outerAngle and points past it are left dark. Angles are half
+// angles of the cones in radians
+func NewSpotLight(color *Color, position, direction *algebra.Vector, innerAngle, outerAngle float64) *SpotLight {
+	d, err := algebra.NewVector(direction.Get()[0], direction.Get()[1], direction.Get()[2]).Normalize()
+	if err != nil {
+		panic(err)
+	}
+	if innerAngle > outerAngle {
+		innerAngle = outerAngle
 	}
+	return &SpotLight{PointLight: PointLight{Intensity: color, Position: position}, Direction: d,
+		InnerAngle: innerAngle, OuterAngle: outerAngle}
+}
 
-	effectiveColor := Multiply(color, light.Intensity)
+//IntensityAt returns the color of the light reaching p, scaled by the Spotlight factor
+func (l *SpotLight) IntensityAt(p *algebra.Vector) *Color {
+	return l.Intensity.ScalarMult(l.Spotlight(p))
+}
 
-	lightVector, err := light.Position.Subtract(illuminatedPoint)
+//Spotlight returns the fraction of the light reaching the point p. It is 1 inside the inner cone, 0 outside the
+// outer cone and goes down smoothly in between
+func (l *SpotLight) Spotlight(p *algebra.Vector) float64 {
+	toPoint, err := p.Subtract(l.Position)
 	if err != nil {
 		panic(err)
-		return nil
 	}
-	lightVector, err = lightVector.Normalize()
+	toPoint, err = toPoint.Normalize()
+	if err != nil {
+		// the point is the light itself
+		return 1
+	}
+	cos, err := algebra.DotProduct(toPoint, l.Direction)
 	if err != nil {
 		panic(err)
-		return nil
 	}
-
-	ambient := effectiveColor.ScalarMult(material.Ambient)
-
-	if inShadow {
-		return ambient
+	cosInner, cosOuter := math.Cos(l.InnerAngle), math.Cos(l.OuterAngle)
+	if cos >= cosInner {
+		return 1
+	}
+	if cos <= cosOuter {
+		return 0
 	}
+	t := (cos - cosOuter) / (cosInner - cosOuter)
+	return t * t * (3 - 2*t)
+}
+
+//DirectionalLight is a light at infinity, such as the sun, whose rays are all parallel to its Direction
+type DirectionalLight struct {
+	Intensity *Color
+	Direction *algebra.Vector // unit vector the rays of the light travel along
+}
 
-	lightDotNormal, err := algebra.DotProduct(lightVector, normalVector)
+//NewDirectionalLight returns a new light at infinity whose parallel rays travel along direction
+func NewDirectionalLight(color *Color, direction *algebra.Vector) *DirectionalLight {
+	d, err := algebra.NewVector(direction.Get()[0], direction.Get()[1], direction.Get()[2]).Normalize()
 	if err != nil {
 		panic(err)
-		return nil
 	}
-	var diffuse *Color
-	var specular *Color
-	if lightDotNormal < 0 {
-		diffuse = &Color{0, 0, 0}
-		specular = &Color{0, 0, 0}
+	return &DirectionalLight{Intensity: color, Direction: d}
+}
+
+//GetIntensity returns the color of the light
+func (l *DirectionalLight) GetIntensity() *Color {
+	return l.Intensity
+}
+
+//IntensityAt returns the color of the light, which is the same everywhere
+func (l *DirectionalLight) IntensityAt(p *algebra.Vector) *Color {
+	return l.Intensity
+}
+
+//GetFalloff returns NoFalloff, a light at infinity is as bright everywhere
+func (l *DirectionalLight) GetFalloff() Falloff {
+	return NoFalloff
+}
+
+//Samples returns the direction pointing to the light
+func (l *DirectionalLight) Samples(p *algebra.Vector) []*algebra.Vector {
+	return []*algebra.Vector{l.Direction.Negate()}
+}
+
+//DirectionFrom returns the direction pointing to the light from any point, at an infinite distance
+func (l *DirectionalLight) DirectionFrom(p, sample *algebra.Vector) (*algebra.Vector, float64) {
+	return l.Direction.Negate(), math.Inf(1)
+}
+
+//ShadowRay returns the ray cast from p towards the light, every object it hits blocks the light
+func (l *DirectionalLight) ShadowRay(p, sample *algebra.Vector) (*algebra.Ray, float64) {
+	return shadowRay(l, p, sample)
+}
+
+//Lighting computes the lighting from the Light onto the Material at the illuminatedPoint with its normal Vector
+// from the point of view of the eye vector
+func Lighting(material *Material, patternColor *Color, light Light, illuminatedPoint, eyeVector, normalVector *algebra.Vector, inShadow bool) *Color {
+	visibility := &Color{1, 1, 1}
+	if inShadow {
+		visibility = &Color{0, 0, 0}
+	}
+	samples, weights := LightSamples(light, illuminatedPoint)
+	return LightingSamples(material, patternColor, light, samples, weights, illuminatedPoint, eyeVector, normalVector,
+		visibility)
+}
+
+//LightingSamples computes the lighting onto the Material from the given samples of the light. The diffuse and
+// specular terms are attenuated by the falloff of the light with the distance to each sample, multiplied by the weight
+// of the sample when weights is not nil, averaged over the samples and scaled by the visibility of the light from the
+// illuminatedPoint, which goes for each color channel from 0 when it is fully in shadow to 1 when it is fully lit.
+// The ambient term is attenuated by the falloff too but is not shadowed, a WeightedLight gives none
+func LightingSamples(material *Material, patternColor *Color, light Light, samples []*algebra.Vector, weights []*Color, illuminatedPoint, eyeVector, normalVector *algebra.Vector, visibility *Color) *Color {
+	var color *Color
+	if patternColor != nil {
+		color = patternColor
 	} else {
-		diffuse = effectiveColor.ScalarMult(material.Diffuse).ScalarMult(lightDotNormal)
+		color = material.Color
+	}
+
+	ambient := Multiply(color, ambientIntensity(light, samples, illuminatedPoint)).ScalarMult(material.Ambient)
+
+	if (visibility[0] <= 0 && visibility[1] <= 0 && visibility[2] <= 0) || len(samples) == 0 {
+		return ambient
+	}
+
+	intensity := light.IntensityAt(illuminatedPoint)
+	sum := &Color{0, 0, 0}
+	for k, sample := range samples {
+		lightVector, dist := light.DirectionFrom(illuminatedPoint, sample)
+		attenuation := light.GetFalloff().Attenuation(dist)
+		lightColor := intensity
+		if weights != nil {
+			lightColor = Multiply(intensity, weights[k])
+		}
+
+		lightDotNormal, err := algebra.DotProduct(lightVector, normalVector)
+		if err != nil {
+			panic(err)
+			return nil
+		}
+		if lightDotNormal < 0 {
+			continue
+		}
+		diffuse := Multiply(color, lightColor).ScalarMult(material.Diffuse * lightDotNormal * attenuation)
+		sum = sum.Add(diffuse)
 
 		reflectVector := lightVector.Negate().Reflect(normalVector)
 		reflectDotEye, err := algebra.DotProduct(reflectVector, eyeVector)
@@ -64,14 +244,52 @@ func Lighting(material *Material, patternColor *Color, light *PointLight, illumi
 			panic(err)
 			return nil
 		}
-
-		if reflectDotEye < 0 {
-			specular = &Color{0, 0, 0}
-		} else {
+		if reflectDotEye > 0 {
 			factor := math.Pow(reflectDotEye, material.Shininess)
-			specular = light.Intensity.ScalarMult(material.Specular).ScalarMult(factor)
+			sum = sum.Add(lightColor.ScalarMult(material.Specular * factor * attenuation))
 		}
 	}
 
-	return ambient.Add(diffuse).Add(specular)
+	return ambient.Add(Multiply(sum, visibility).ScalarMult(1 / float64(len(samples))))
+}
+
+//ambientIntensity returns the light of the ambient term at the illuminatedPoint, the intensity of the light shining
+// towards it attenuated by its falloff averaged over the samples, so that the ambient term fades with the distance
+// like the rest of the light and a SpotLight gives none outside of its cone. A WeightedLight, whose light comes from
+// the weights of its samples, gives no ambient term
+func ambientIntensity(light Light, samples []*algebra.Vector, illuminatedPoint *algebra.Vector) *Color {
+	if _, weighted := light.(WeightedLight); weighted {
+		return &Color{0, 0, 0}
+	}
+	intensity := light.IntensityAt(illuminatedPoint)
+	if len(samples) == 0 {
+		return intensity
+	}
+	attenuation := 0.0
+	for _, sample := range samples {
+		_, dist := light.DirectionFrom(illuminatedPoint, sample)
+		attenuation += light.GetFalloff().Attenuation(dist)
+	}
+	return intensity.ScalarMult(attenuation / float64(len(samples)))
+}
+
+//directionTo returns the unit vector pointing from p to the point sample and the distance between them
+func directionTo(p, sample *algebra.Vector) (*algebra.Vector, float64) {
+	v, err := sample.Subtract(p)
+	if err != nil {
+		panic(err)
+	}
+	dist := v.Magnitude()
+	v, err = v.Normalize()
+	if err != nil {
+		panic(err)
+	}
+	return v, dist
+}
+
+//shadowRay returns the ray from p along the direction of the sample of the light and the distance to the sample
+func shadowRay(l Light, p, sample *algebra.Vector) (*algebra.Ray, float64) {
+	direction, dist := l.DirectionFrom(p, sample)
+	origin := []float64{p.Get()[0], p.Get()[1], p.Get()[2]}
+	return algebra.NewRay(append(origin, direction.Get()[:3]...)...), dist
 }
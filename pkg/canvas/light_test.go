@@ -13,6 +13,18 @@ func TestNewPointLight(t *testing.T) {
 	testVectorEquals(t, l.Intensity, &Color{1, 1, 1})
 }
 
+func TestPointLight_ShadowRay(t *testing.T) {
+	l := NewPointLight(&Color{1, 1, 1}, algebra.NewPoint(0, 10, 0))
+	p := algebra.NewPoint(0, 0, 0)
+	r, dist := l.ShadowRay(p, l.Samples(p)[0])
+
+	testRealVectorEquals(t, r.Get()["origin"].Get(), []float64{0, 0, 0, 1})
+	testRealVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 1, 0, 0})
+	if !equals(dist, 10) {
+		t.Errorf("Expected the shadow ray to stop at the light, Got: %f", dist)
+	}
+}
+
 func testRealVectorEquals(t *testing.T, vector []float64, expected []float64) {
 	if len(vector) != len(expected) {
 		t.Errorf("Mismatched vector lengths, Expected : %d, Got: %d", len(expected), len(vector))
@@ -73,3 +85,90 @@ func TestLighting(t *testing.T) {
 	testVectorEquals(t, c1, &Color{1, 1, 1})
 	testVectorEquals(t, c2, &Color{0, 0, 0})
 }
+
+func TestNewSpotLight(t *testing.T) {
+	l := NewSpotLight(&Color{1, 1, 1}, algebra.NewPoint(0, 10, 0), algebra.NewVector(0, -2, 0), math.Pi/4, math.Pi/8)
+
+	testRealVectorEquals(t, l.Direction.Get(), []float64{0, -1, 0, 0})
+	if l.InnerAngle != math.Pi/8 || l.OuterAngle != math.Pi/8 {
+		t.Errorf("Expected the inner angle to be clamped to the outer angle, Got: %f, %f", l.InnerAngle, l.OuterAngle)
+	}
+}
+
+func TestSpotLight_Spotlight(t *testing.T) {
+	spot := NewSpotLight(&Color{1, 1, 1}, algebra.NewPoint(0, 0, -10), algebra.NewVector(0, 0, 1),
+		math.Pi/18, math.Pi/9)
+	if s := spot.Spotlight(algebra.NewPoint(0, 0, 0)); s != 1 {
+		t.Errorf("Expected points inside the inner cone to be fully lit, Got: %f", s)
+	}
+	if s := spot.Spotlight(algebra.NewPoint(10, 0, 0)); s != 0 {
+		t.Errorf("Expected points outside the outer cone to be dark, Got: %f", s)
+	}
+	previous := 1.0
+	for _, x := range []float64{1.9, 2.3, 2.7, 3.1, 3.5} {
+		s := spot.Spotlight(algebra.NewPoint(x, 0, 0))
+		if s <= 0 || s >= 1 || s >= previous {
+			t.Errorf("Expected the light to fade out between the cones, Got: %f at x = %f", s, x)
+		}
+		previous = s
+	}
+}
+
+func TestLightingSpotLight(t *testing.T) {
+	m := NewDefaultMaterial()
+	eyeVector := algebra.NewVector(0, 0, -1)
+	normalVector := algebra.NewVector(0, 0, -1)
+	light := NewSpotLight(&Color{1, 1, 1}, algebra.NewPoint(0, 0, -10), algebra.NewVector(0, 0, 1),
+		math.Pi/18, math.Pi/9)
+
+	color := Lighting(m, nil, light, algebra.NewPoint(0, 0, 0), eyeVector, normalVector, false)
+	testVectorEquals(t, color, &Color{1.9, 1.9, 1.9})
+
+	// points outside of the outer cone get no light at all, not even the ambient term
+	color = Lighting(m, nil, light, algebra.NewPoint(10, 0, 0), eyeVector, normalVector, false)
+	testVectorEquals(t, color, &Color{0, 0, 0})
+
+	color = Lighting(m, nil, light, algebra.NewPoint(0, 0, 0), eyeVector, normalVector, true)
+	testVectorEquals(t, color, &Color{0.1, 0.1, 0.1})
+}
+
+func TestNewDirectionalLight(t *testing.T) {
+	l := NewDirectionalLight(&Color{1, 1, 1}, algebra.NewVector(0, -3, 0))
+
+	p := algebra.NewPoint(5, -100, 7)
+	samples := l.Samples(p)
+	if len(samples) != 1 {
+		t.Fatalf("Expected a single sample, Got: %d", len(samples))
+	}
+	v, dist := l.DirectionFrom(p, samples[0])
+	testRealVectorEquals(t, v.Get(), []float64{0, 1, 0, 0})
+	if !math.IsInf(dist, 1) {
+		t.Errorf("Expected a directional light to be infinitely far, Got: %f", dist)
+	}
+	r, dist := l.ShadowRay(p, samples[0])
+	testRealVectorEquals(t, r.Get()["origin"].Get(), []float64{5, -100, 7, 1})
+	testRealVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 1, 0, 0})
+	if !math.IsInf(dist, 1) {
+		t.Errorf("Expected shadow rays of a directional light to have no maximum distance, Got: %f", dist)
+	}
+}
+
+func TestLightingDirectional(t *testing.T) {
+	m := NewDefaultMaterial()
+	eyeVector := algebra.NewVector(0, 0, -1)
+	normalVector := algebra.NewVector(0, 0, -1)
+	light := NewDirectionalLight(&Color{1, 1, 1}, algebra.NewVector(0, 0, 1))
+
+	// the light is the same wherever the point is
+	for _, p := range []*algebra.Vector{algebra.NewPoint(0, 0, 0), algebra.NewPoint(50, -20, 3)} {
+		color := Lighting(m, nil, light, p, eyeVector, normalVector, false)
+		testVectorEquals(t, color, &Color{1.9, 1.9, 1.9})
+	}
+
+	light = NewDirectionalLight(&Color{1, 1, 1}, algebra.NewVector(0, -1, 1))
+	color := Lighting(m, nil, light, algebra.NewPoint(0, 0, 0), eyeVector, normalVector, false)
+	testVectorEquals(t, color, &Color{0.7364, 0.7364, 0.7364})
+
+	color = Lighting(m, nil, light, algebra.NewPoint(0, 0, 0), eyeVector, normalVector, true)
+	testVectorEquals(t, color, &Color{0.1, 0.1, 0.1})
+}
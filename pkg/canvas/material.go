@@ -2,15 +2,17 @@ package canvas
 
 //Material encapsulates surface color but also lighting parameters of a surface
 type Material struct {
-	Color           *Color
-	Ambient         float64
-	Diffuse         float64
-	Specular        float64
-	Shininess       float64
-	Reflective      float64
-	Transparency    float64
-	RefractiveIndex float64
-	Pattern         *Pattern
+	Color            *Color
+	Ambient          float64
+	Diffuse          float64
+	Specular         float64
+	Shininess        float64
+	Reflective       float64
+	Transparency     float64
+	RefractiveIndex  float64
+	Pattern          *Pattern
+	Emission         *Color  // color of the light given off by the surface, nil for surfaces that do not glow
+	EmissionStrength float64 // multiplies the Emission color
 }
 
 //NewDefaultMaterial creates a material with preset default values
@@ -23,3 +25,17 @@ func NewDefaultMaterial() *Material {
 func NewMaterial(color *Color, ambient, diffuse, specular, shininess float64) *Material {
 	return &Material{Color: color, Ambient: ambient, Diffuse: diffuse, Specular: specular, Shininess: shininess, Pattern: nil}
 }
+
+//Emitted returns the light given off by the surface, black unless the material has an Emission
+func (m *Material) Emitted() *Color {
+	if m.Emission == nil {
+		return &Color{0, 0, 0}
+	}
+	return m.Emission.ScalarMult(m.EmissionStrength)
+}
+
+//IsEmissive returns whether the surface gives off light
+func (m *Material) IsEmissive() bool {
+	e := m.Emitted()
+	return e[0] > 0 || e[1] > 0 || e[2] > 0
+}
@@ -32,3 +32,18 @@ func assertEquals(t *testing.T, got, expected float64) {
 		t.Errorf("Expected %f, Got: %f", expected, got)
 	}
 }
+
+func TestMaterial_Emitted(t *testing.T) {
+	m := NewDefaultMaterial()
+	testVectorEquals(t, m.Emitted(), &Color{0, 0, 0})
+	if m.IsEmissive() {
+		t.Errorf("Expected the default material not to be emissive")
+	}
+
+	m.Emission = &Color{1, 0.5, 0}
+	m.EmissionStrength = 3
+	testVectorEquals(t, m.Emitted(), &Color{3, 1.5, 0})
+	if !m.IsEmissive() {
+		t.Errorf("Expected the material to be emissive")
+	}
+}
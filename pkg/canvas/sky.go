@@ -0,0 +1,225 @@
+package canvas
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"math"
+)
+
+//SOLARILLUMINANCE is the illuminance of the sun outside the atmosphere in klux. The luminance of a Sky, in kcd/m^2,
+// is divided by it so that a sun of intensity 1 and the sky light the scene in the right proportions, as long as
+// their irradiance is reflected the same way: with the 1/pi of a Lambertian surface by the PathTracer and without it
+// by the Phong model of World.ShadeHit
+var SOLARILLUMINANCE = 128.0
+
+//GROUNDALBEDO is the fraction of the light of the sky and the sun reflected by the ground seen below the horizon of
+// a Sky
+var GROUNDALBEDO = 0.3
+
+//IrradianceBackground is a Background lighting the surfaces of the World in World.ShadeHit, like a colored ambient
+// term coming from every direction of the sky. Its light is not shadowed
+type IrradianceBackground interface {
+	Background
+	//Irradiance returns the light of the background falling on a surface facing the normal, integrated over the
+	// directions of the background weighted by their cosine with the normal
+	Irradiance(normal *algebra.Vector) *Color
+}
+
+//perez holds the coefficients of the Perez model of the distribution of the light over the sky
+type perez struct {
+	a, b, c, d, e float64
+}
+
+//at returns the Perez function for a direction at the angle theta from the zenith and gamma from the sun
+func (p perez) at(cosTheta, gamma float64) float64 {
+	return (1 + p.a*math.Exp(p.b/cosTheta)) * (1 + p.c*math.Exp(p.d*gamma) + p.e*math.Cos(gamma)*math.Cos(gamma))
+}
+
+//Sky is an analytic daylight sky following the model of Preetham, Shirley and Smits. The light of the sky depends
+// on the position of the sun and on the turbidity of the atmosphere, the haze that whitens the sky around the sun and
+// the horizon. It is both a SampledBackground, for the PathTracer, and an IrradianceBackground, for World.ShadeHit.
+// Pair it with the DirectionalLight returned by Sun
+type Sky struct {
+	elevation float64 // angle of the sun above the horizon, in radians
+	azimuth   float64 // longitude of the sun, in radians, 0 towards -z and pi/2 towards -x like an EnvironmentMap
+	turbidity float64
+	intensity float64
+	sun       *algebra.Vector // unit vector pointing to the sun
+	thetaSun  float64         // angle of the sun from the zenith
+	zenith    [3]float64      // x, y chromaticity and luminance Y at the zenith
+	perez     [3]perez        // coefficients for x, y and Y
+	ground    *Color          // color of the ground below the horizon
+	sampler   *EnvironmentMap // low resolution image of the sky sampled by luminance
+	irradiant *EnvironmentMap // irradiance for every normal
+}
+
+//NewSky returns a new Sky with the sun at the given elevation above the horizon and azimuth, in radians, and the
+// given turbidity, from 2 for a clear sky to 10 for a hazy one, turbidities outside of that range, where the model
+// does not hold, are clamped to it. The azimuth is the longitude of the sun, as in an
+// EnvironmentMap: 0 puts the sun towards -z and pi/2 towards -x. The light of the sky, and of its Sun, is multiplied
+// by intensity
+func NewSky(elevation, azimuth, turbidity, intensity float64) *Sky {
+	t := math.Min(math.Max(2, turbidity), 10)
+	s := &Sky{elevation: elevation, azimuth: azimuth, turbidity: t, intensity: intensity}
+	s.sun = algebra.NewVector(-math.Sin(azimuth)*math.Cos(elevation), math.Sin(elevation),
+		-math.Cos(azimuth)*math.Cos(elevation))
+	s.thetaSun = math.Min(math.Pi/2-elevation, math.Pi/2)
+
+	theta, theta2 := s.thetaSun, s.thetaSun*s.thetaSun
+	theta3, t2 := theta2*theta, t*t
+	chi := (4.0/9 - t/120) * (math.Pi - 2*theta)
+	s.zenith[2] = math.Max(0, (4.0453*t-4.9710)*math.Tan(chi)-0.2155*t+2.4192)
+	s.zenith[0] = t2*(0.00166*theta3-0.00375*theta2+0.00209*theta) +
+		t*(-0.02903*theta3+0.06377*theta2-0.03202*theta+0.00394) +
+		(0.11693*theta3 - 0.21196*theta2 + 0.06052*theta + 0.25886)
+	s.zenith[1] = t2*(0.00275*theta3-0.00610*theta2+0.00317*theta) +
+		t*(-0.04214*theta3+0.08970*theta2-0.04153*theta+0.00516) +
+		(0.15346*theta3 - 0.26756*theta2 + 0.06670*theta + 0.26688)
+	s.perez[0] = perez{-0.0193*t - 0.2592, -0.0665*t + 0.0008, -0.0004*t + 0.2125, -0.0641*t - 0.8989,
+		-0.0033*t + 0.0452}
+	s.perez[1] = perez{-0.0167*t - 0.2608, -0.0950*t + 0.0092, -0.0079*t + 0.2102, -0.0441*t - 1.6537,
+		-0.0109*t + 0.0529}
+	s.perez[2] = perez{0.1787*t - 1.4630, -0.3554*t + 0.4275, -0.0227*t + 5.3251, 0.1206*t - 2.5771,
+		-0.0670*t + 0.3703}
+
+	// the ground is lit from above by the sky alone, then by the sun
+	s.ground = &Color{0, 0, 0}
+	up := irradiance(s.image(64, 32), []*algebra.Vector{algebra.NewVector(0, 1, 0)})[0]
+	sun := s.Sun().GetIntensity().ScalarMult(math.Max(0, math.Sin(elevation)))
+	s.ground = up.Add(sun).ScalarMult(GROUNDALBEDO / math.Pi)
+
+	s.sampler = NewEnvironmentMap(s.image(64, 32), 1)
+	normals := make([]*algebra.Vector, 0, 32*16)
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 32; x++ {
+			normals = append(normals, equirectangularDirection(32, 16, float64(x)+0.5, float64(y)+0.5))
+		}
+	}
+	irradiant := NewCanvas(32, 16)
+	for i, c := range irradiance(s.sampler.Image, normals) {
+		irradiant.Pixels[i/32][i%32] = c
+	}
+	s.irradiant = NewEnvironmentMap(irradiant, 1)
+	return s
+}
+
+//SunDirection returns the unit vector pointing from the scene to the sun
+func (s *Sky) SunDirection() *algebra.Vector {
+	return s.sun
+}
+
+//Sun returns the DirectionalLight of the sun of the sky, dimmed and reddened by the atmosphere it goes through,
+// which is thicker when the sun is low and the turbidity high. The sun is black once it has set
+func (s *Sky) Sun() *DirectionalLight {
+	if s.elevation < 0 {
+		return NewDirectionalLight(&Color{0, 0, 0}, s.sun.Negate())
+	}
+	thetaDegrees := s.thetaSun * 180 / math.Pi
+	// relative optical mass of the atmosphere crossed by the light of the sun
+	mass := 1 / (math.Cos(s.thetaSun) + 0.15*math.Pow(93.885-thetaDegrees, -1.253))
+	beta := 0.04608365822050*s.turbidity - 0.04586025928522
+	color := &Color{}
+	// wavelengths of red, green and blue in micrometres
+	for i, lambda := range []float64{0.680, 0.550, 0.440} {
+		rayleigh := 0.008735 * math.Pow(lambda, -4.08)
+		aerosol := beta * math.Pow(lambda, -1.3)
+		color[i] = s.intensity * math.Exp(-(rayleigh+aerosol)*mass)
+	}
+	return NewDirectionalLight(color, s.sun.Negate())
+}
+
+//ColorAt returns the light of the sky coming from the direction. Directions below the horizon see a uniform ground
+// of albedo GROUNDALBEDO lit by the sky and the sun
+func (s *Sky) ColorAt(direction *algebra.Vector) *Color {
+	d, err := algebra.NewVector(direction.Get()[0], direction.Get()[1], direction.Get()[2]).Normalize()
+	if err != nil {
+		return &Color{0, 0, 0}
+	}
+	if d.Get()[1] < 0 {
+		return s.ground
+	}
+	cosTheta := math.Max(d.Get()[1], 0.001)
+	cosGamma, err := algebra.DotProduct(d, s.sun)
+	if err != nil {
+		panic(err)
+	}
+	gamma := math.Acos(math.Max(-1, math.Min(1, cosGamma)))
+
+	var xyY [3]float64
+	for i := range xyY {
+		xyY[i] = s.zenith[i] * s.perez[i].at(cosTheta, gamma) / s.perez[i].at(1, s.thetaSun)
+	}
+	if xyY[1] <= 0 {
+		return &Color{0, 0, 0}
+	}
+	luminance := xyY[2] * s.intensity / SOLARILLUMINANCE
+	x := xyY[0] / xyY[1] * luminance
+	z := (1 - xyY[0] - xyY[1]) / xyY[1] * luminance
+	// CIE XYZ to linear sRGB
+	return &Color{
+		math.Max(0, 3.2406*x-1.5372*luminance-0.4986*z),
+		math.Max(0, -0.9689*x+1.8758*luminance+0.0415*z),
+		math.Max(0, 0.0557*x-0.2040*luminance+1.0570*z)}
+}
+
+//Sample returns a direction of the sky picked in proportion to its luminance, the light coming from it and the
+// probability density of picking it per unit solid angle
+func (s *Sky) Sample(u, v float64) (*algebra.Vector, *Color, float64) {
+	direction, _, pdf := s.sampler.Sample(u, v)
+	return direction, s.ColorAt(direction), pdf
+}
+
+//Irradiance returns the light of the sky falling on a surface facing the normal
+func (s *Sky) Irradiance(normal *algebra.Vector) *Color {
+	return s.irradiant.ColorAt(normal)
+}
+
+//image returns an equirectangular image of the sky, mapped like an EnvironmentMap
+func (s *Sky) image(width, height int) *Canvas {
+	image := NewCanvas(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			image.Pixels[y][x] = s.ColorAt(equirectangularDirection(width, height, float64(x)+0.5, float64(y)+0.5))
+		}
+	}
+	return image
+}
+
+//irradiance returns the light of the equirectangular image falling on surfaces facing each of the normals, summing
+// the light of its pixels weighted by their cosine with the normal and the solid angle they cover
+func irradiance(image *Canvas, normals []*algebra.Vector) []*Color {
+	directions := make([]*algebra.Vector, 0, image.Width*image.Height)
+	lights := make([]*Color, 0, image.Width*image.Height)
+	for y := 0; y < image.Height; y++ {
+		latitude := (0.5 - (float64(y)+0.5)/float64(image.Height)) * math.Pi
+		solidAngle := 2 * math.Pi * math.Pi * math.Cos(latitude) / float64(image.Width*image.Height)
+		for x := 0; x < image.Width; x++ {
+			directions = append(directions, equirectangularDirection(image.Width, image.Height, float64(x)+0.5,
+				float64(y)+0.5))
+			lights = append(lights, image.Pixels[y][x].ScalarMult(solidAngle))
+		}
+	}
+	result := make([]*Color, 0, len(normals))
+	for _, normal := range normals {
+		sum := &Color{0, 0, 0}
+		for i, d := range directions {
+			cos, err := algebra.DotProduct(normal, d)
+			if err != nil {
+				panic(err)
+			}
+			if cos > 0 {
+				sum = sum.Add(lights[i].ScalarMult(cos))
+			}
+		}
+		result = append(result, sum)
+	}
+	return result
+}
+
+//equirectangularDirection returns the unit vector seen at the point (x, y), in pixels, of a width x height
+// equirectangular image
+func equirectangularDirection(width, height int, x, y float64) *algebra.Vector {
+	longitude := (x/float64(width) - 0.5) * 2 * math.Pi
+	latitude := (0.5 - y/float64(height)) * math.Pi
+	return algebra.NewVector(-math.Sin(longitude)*math.Cos(latitude), math.Sin(latitude),
+		-math.Cos(longitude)*math.Cos(latitude))
+}
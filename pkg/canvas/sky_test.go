@@ -0,0 +1,99 @@
+package canvas
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNewSky(t *testing.T) {
+	s := NewSky(math.Pi/2, 0, 3, 1)
+	testNear(t, s.SunDirection().Get()[:3], []float64{0, 1, 0})
+	s = NewSky(0, 0, 3, 1)
+	testNear(t, s.SunDirection().Get()[:3], []float64{0, 0, -1})
+	s = NewSky(math.Pi/4, math.Pi/2, 3, 1)
+	testNear(t, s.SunDirection().Get()[:3], []float64{-math.Sqrt2 / 2, math.Sqrt2 / 2, 0})
+	testNear(t, s.Sun().Direction.Get()[:3], []float64{math.Sqrt2 / 2, -math.Sqrt2 / 2, 0})
+
+	// turbidities outside of the range of the model are clamped, the horizon stays finite and lit
+	horizon := algebra.NewVector(0.3, 0.01, 1)
+	for _, turbidity := range [][2]float64{{1, 2}, {1.5, 2}, {20, 10}} {
+		c := NewSky(0.8, 0, turbidity[0], 1).ColorAt(horizon)
+		testNear(t, c[:], NewSky(0.8, 0, turbidity[1], 1).ColorAt(horizon)[:])
+		for i := range c {
+			if c[i] <= 0 || c[i] > 1 {
+				t.Errorf("Expected the horizon of a sky of turbidity %f to be lit, got %v", turbidity[0], c)
+			}
+		}
+	}
+}
+
+func TestSky_Sun(t *testing.T) {
+	high := NewSky(1.2, 0, 3, 1).Sun().GetIntensity()
+	low := NewSky(0.05, 0, 3, 1).Sun().GetIntensity()
+	hazy := NewSky(1.2, 0, 8, 1).Sun().GetIntensity()
+	for i := range high {
+		if high[i] <= 0 || high[i] > 1 {
+			t.Errorf("Expected the sun to be dimmed by the atmosphere, got %v", high)
+		}
+		if low[i] >= high[i] || hazy[i] >= high[i] {
+			t.Errorf("Expected a low sun %v and a hazy sun %v dimmer than %v", low, hazy, high)
+		}
+	}
+	// the low sun is redder
+	if low.Blue()/low.Red() >= high.Blue()/high.Red() {
+		t.Errorf("Expected a low sun %v redder than %v", low, high)
+	}
+	testNear(t, NewSky(-0.1, 0, 3, 1).Sun().GetIntensity()[:], []float64{0, 0, 0})
+	testNear(t, NewSky(1.2, 0, 3, 2).Sun().GetIntensity()[:], high.ScalarMult(2)[:])
+}
+
+func TestSky_ColorAt(t *testing.T) {
+	s := NewSky(1.2, 0, 3, 1)
+	zenith := s.ColorAt(algebra.NewVector(0, 1, 0))
+	if zenith.Blue() <= zenith.Red() {
+		t.Errorf("Expected a blue zenith, got %v", zenith)
+	}
+	// the sky is brighter around the sun than away from it
+	around := s.ColorAt(s.SunDirection())
+	away := s.ColorAt(algebra.NewVector(0, 0.3, 1))
+	if luminance(around) <= luminance(away) {
+		t.Errorf("Expected the sky around the sun %v brighter than %v", around, away)
+	}
+	testNear(t, s.ColorAt(algebra.NewVector(0, 2, 0))[:], zenith[:])
+	// below the horizon is the ground lit by the sun and the sky
+	ground := s.ColorAt(algebra.NewVector(0.2, -1, 0.3))
+	testNear(t, ground[:], s.ColorAt(algebra.NewVector(0, -1, 0))[:])
+	if luminance(ground) <= 0 {
+		t.Errorf("Expected a lit ground, got %v", ground)
+	}
+	doubled := NewSky(1.2, 0, 3, 2).ColorAt(algebra.NewVector(0.5, 0.5, 0))
+	testNear(t, doubled[:], s.ColorAt(algebra.NewVector(0.5, 0.5, 0)).ScalarMult(2)[:])
+}
+
+func TestSky_Sample(t *testing.T) {
+	s := NewSky(0.4, 1, 3, 1)
+	rng := rand.New(rand.NewSource(1))
+	// the samples estimate the light falling on an upward surface
+	sum := &Color{0, 0, 0}
+	n := 20000
+	for i := 0; i < n; i++ {
+		direction, c, pdf := s.Sample(rng.Float64(), rng.Float64())
+		if pdf <= 0 {
+			t.Fatalf("Expected a positive pdf for %v", direction.Get())
+		}
+		testNear(t, c[:], s.ColorAt(direction)[:])
+		if cos := direction.Get()[1]; cos > 0 {
+			sum = sum.Add(c.ScalarMult(cos / pdf))
+		}
+	}
+	estimate := sum.ScalarMult(1 / float64(n))
+	irradiance := s.Irradiance(algebra.NewVector(0, 1, 0))
+	for i := range estimate {
+		if math.Abs(estimate[i]-irradiance[i]) > 0.05*irradiance[i] {
+			t.Errorf("Expected the samples to estimate the irradiance %v, got %v", irradiance, estimate)
+			break
+		}
+	}
+}
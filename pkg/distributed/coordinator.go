@@ -0,0 +1,352 @@
+package distributed
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/camera"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//DEFAULTLEASE is how long a worker has to post back a tile before it is handed to another worker
+var DEFAULTLEASE = 2 * time.Minute
+
+//DEFAULTATTEMPTS is how many times a tile is handed out before the render is given up
+var DEFAULTATTEMPTS = 5
+
+//Job is a tile handed to a worker, along with the size of the whole image so the worker can check it renders the
+// same camera as the coordinator
+type Job struct {
+	Tile   camera.Tile `json:"tile"`
+	Width  int         `json:"width"`
+	Height int         `json:"height"`
+}
+
+//Result holds the pixels of a rendered tile, row by row, as red, green and blue values
+type Result struct {
+	Tile   camera.Tile `json:"tile"`
+	Pixels []float64   `json:"pixels"`
+}
+
+//tileState tracks a tile of the image while it is handed to workers
+type tileState struct {
+	tile     camera.Tile
+	attempts int       // number of times the tile was handed out
+	deadline time.Time // time by which the current lease expires, zero when the tile is not leased
+	done     bool
+}
+
+//workerState tracks a worker that asked for tiles, so the coordinator knows when every worker was told the render is
+// over
+type workerState struct {
+	seen time.Time // time of the last request of the worker
+	told bool      // whether the worker was answered that the render is over
+}
+
+//Coordinator splits an image into tiles and serves them over HTTP to workers. It is an http.Handler where
+// GET /job hands out the next tile as a Job, or answers 204 when every tile is leased and 410 once the image is done,
+// and POST /result takes the Result of a tile. A tile whose lease expires, because its worker died or is too slow,
+// goes back to the queue and is handed to the next worker asking for one, or fails the render once it was handed out
+// too many times. Results are stitched into the image as they arrive. Workers name themselves with a worker query
+// parameter, so that the coordinator can wait for each of them to be told the render is over
+type Coordinator struct {
+	mu       sync.Mutex
+	width    int
+	height   int
+	image    *canvas.Canvas
+	tiles    []*tileState
+	queue    []*tileState // tiles waiting to be handed out, in order
+	left     int          // tiles not done yet
+	lease    time.Duration
+	attempts int
+	err      error         // set when a tile failed too many times
+	finished chan struct{} // closed once every tile is done or the render failed
+	workers  map[string]*workerState
+	told     chan struct{} // signalled when a worker is told the render is over
+	mux      *http.ServeMux
+}
+
+//NewCoordinator returns a coordinator for a width x height image split into tiles of size x size pixels
+func NewCoordinator(width, height, size int) *Coordinator {
+	c := &Coordinator{width: width, height: height, image: canvas.NewCanvas(width, height),
+		lease: DEFAULTLEASE, attempts: DEFAULTATTEMPTS, finished: make(chan struct{}),
+		workers: make(map[string]*workerState), told: make(chan struct{}, 1)}
+	for _, t := range camera.Tiles(width, height, size) {
+		state := &tileState{tile: t}
+		c.tiles = append(c.tiles, state)
+		c.queue = append(c.queue, state)
+	}
+	c.left = len(c.tiles)
+	if c.left == 0 {
+		close(c.finished)
+	}
+	c.mux = http.NewServeMux()
+	c.mux.HandleFunc("/job", c.serveJob)
+	c.mux.HandleFunc("/result", c.serveResult)
+	return c
+}
+
+//SetLease sets how long a worker has to post back a tile before it is handed to another worker
+func (c *Coordinator) SetLease(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lease = d
+}
+
+//SetAttempts sets how many times a tile is handed out before the render fails with a TileFailed error
+func (c *Coordinator) SetAttempts(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts = n
+}
+
+//ServeHTTP serves the job and result endpoints
+func (c *Coordinator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mux.ServeHTTP(w, r)
+}
+
+//Wait blocks until every tile is done and returns the stitched image. When ctx is cancelled first, the partial image
+// is returned along with a camera.RenderInterrupted error, and a TileFailed error is returned when a tile could not
+// be rendered by any worker. Expired leases are reclaimed while waiting, so the render fails once a tile runs out of
+// attempts even when every worker died and none asks for a tile any more. Once the render is over, Wait only returns
+// after every worker was told so, or was not heard from for the length of a lease and is taken for dead, so the
+// server can be shut down without leaving workers asking for tiles
+func (c *Coordinator) Wait(ctx context.Context) (*canvas.Canvas, error) {
+	timer := time.NewTimer(c.untilExpiry(time.Now()))
+	defer timer.Stop()
+wait:
+	for {
+		select {
+		case <-c.finished:
+			break wait
+		case <-ctx.Done():
+			break wait
+		case <-timer.C:
+			now := time.Now()
+			c.mu.Lock()
+			c.reclaim(now)
+			c.mu.Unlock()
+			timer.Reset(c.untilExpiry(now))
+		}
+	}
+	select {
+	case <-c.finished:
+		c.waitWorkers(ctx)
+	default:
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.image, c.err
+	}
+	if c.left > 0 {
+		return c.image, camera.RenderInterrupted{TilesDone: len(c.tiles) - c.left, TilesTotal: len(c.tiles),
+			Err: ctx.Err()}
+	}
+	return c.image, nil
+}
+
+//untilExpiry returns how long until the first lease held by a worker expires, or the length of a lease when no tile
+// is leased since none can expire sooner
+func (c *Coordinator) untilExpiry(now time.Time) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wait := c.lease
+	for _, t := range c.tiles {
+		if !t.done && !t.deadline.IsZero() && t.deadline.Sub(now) < wait {
+			wait = t.deadline.Sub(now)
+		}
+	}
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+//waitWorkers blocks until every worker was told the render is over or was not heard from for the length of a lease,
+// or until ctx is cancelled
+func (c *Coordinator) waitWorkers(ctx context.Context) {
+	for {
+		wait, ok := c.untilWorkersTold(time.Now())
+		if ok {
+			return
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-c.told:
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+		timer.Stop()
+	}
+}
+
+//untilWorkersTold returns whether every worker heard from within a lease was told the render is over, and otherwise
+// how long until the first of the others is taken for dead
+func (c *Coordinator) untilWorkersTold(now time.Time) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var wait time.Duration
+	ok := true
+	for _, wk := range c.workers {
+		left := wk.seen.Add(c.lease).Sub(now)
+		if wk.told || left <= 0 {
+			continue
+		}
+		if ok || left < wait {
+			wait = left
+		}
+		ok = false
+	}
+	return wait, ok
+}
+
+//checkIn records a request of the worker named id, and whether it was told the render is over. Requests without a
+// name are not tracked
+func (c *Coordinator) checkIn(id string, now time.Time, over bool) {
+	if id == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wk, ok := c.workers[id]
+	if !ok {
+		wk = &workerState{}
+		c.workers[id] = wk
+	}
+	wk.seen = now
+	if over && !wk.told {
+		wk.told = true
+		select {
+		case c.told <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *Coordinator) serveJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	now := time.Now()
+	state, status := c.nextTile(now)
+	c.checkIn(r.URL.Query().Get("worker"), now, status == http.StatusGone)
+	if state == nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Job{Tile: state.tile, Width: c.width, Height: c.height})
+}
+
+//nextTile leases the next tile waiting in the queue. Expired leases are put back in the queue first. When there is
+// no tile to hand out the HTTP status telling the worker why is returned instead
+func (c *Coordinator) nextTile(now time.Time) (*tileState, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reclaim(now)
+	if c.left == 0 || c.err != nil {
+		return nil, http.StatusGone
+	}
+	for len(c.queue) > 0 {
+		t := c.queue[0]
+		c.queue = c.queue[1:]
+		if t.done {
+			continue
+		}
+		if t.attempts >= c.attempts {
+			c.fail(t)
+			return nil, http.StatusGone
+		}
+		t.attempts++
+		t.deadline = now.Add(c.lease)
+		return t, http.StatusOK
+	}
+	return nil, http.StatusNoContent
+}
+
+//reclaim puts the tiles whose lease expired by now back in the queue, or fails the render when such a tile was
+// already handed out as many times as allowed. c.mu must be held
+func (c *Coordinator) reclaim(now time.Time) {
+	if c.left == 0 || c.err != nil {
+		return
+	}
+	for _, t := range c.tiles {
+		if t.done || t.deadline.IsZero() || now.Before(t.deadline) {
+			continue
+		}
+		t.deadline = time.Time{}
+		if t.attempts >= c.attempts {
+			c.fail(t)
+			return
+		}
+		log.Printf("Lease of tile (%d, %d) expired, handing it out again", t.tile.X, t.tile.Y)
+		c.queue = append(c.queue, t)
+	}
+}
+
+//fail gives up the render with a TileFailed error for the tile. c.mu must be held
+func (c *Coordinator) fail(t *tileState) {
+	c.err = TileFailed{Tile: t.tile, Attempts: t.attempts}
+	close(c.finished)
+}
+
+func (c *Coordinator) serveResult(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	c.checkIn(r.URL.Query().Get("worker"), time.Now(), false)
+	var result Result
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := c.storeResult(result); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+//storeResult writes the pixels of a finished tile into the image. Late results for tiles that were handed out again
+// are still accepted, the first one to arrive is kept
+func (c *Coordinator) storeResult(result Result) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var state *tileState
+	for _, t := range c.tiles {
+		if t.tile == result.Tile {
+			state = t
+			break
+		}
+	}
+	if state == nil {
+		return InvalidResult{Tile: result.Tile, Reason: "not a tile of the image"}
+	}
+	if len(result.Pixels) != 3*result.Tile.Width*result.Tile.Height {
+		return InvalidResult{Tile: result.Tile, Reason: "wrong number of pixel values"}
+	}
+	if state.done || c.err != nil {
+		return nil
+	}
+	t := result.Tile
+	for y := 0; y < t.Height; y++ {
+		for x := 0; x < t.Width; x++ {
+			i := 3 * (y*t.Width + x)
+			c.image.WritePixel(t.X+x, t.Y+y, &canvas.Color{result.Pixels[i], result.Pixels[i+1], result.Pixels[i+2]})
+		}
+	}
+	state.done = true
+	c.left--
+	if c.left == 0 {
+		close(c.finished)
+	}
+	return nil
+}
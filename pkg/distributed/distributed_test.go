@@ -0,0 +1,240 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/camera"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testCamera(t *testing.T) *camera.Camera {
+	c, err := camera.NewCamera(11, 11, math.Pi/2,
+		algebra.ViewTransform(0, 0, -5, 0, 0, 0, 0, 1, 0))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return c
+}
+
+func testCanvasEquals(t *testing.T, got, expected [][]float64) {
+	for i := range expected {
+		for j := range expected[i] {
+			if math.Abs(got[i][j]-expected[i][j]) > 0.0001 {
+				t.Errorf("Expected %g, Got: %g", expected[i][j], got[i][j])
+				return
+			}
+		}
+	}
+}
+
+func flatten(image *canvas.Canvas) [][]float64 {
+	res := make([][]float64, 0, 0)
+	for _, row := range image.Pixels {
+		values := make([]float64, 0, 0)
+		for _, p := range row {
+			values = append(values, p[:]...)
+		}
+		res = append(res, values)
+	}
+	return res
+}
+
+func TestCoordinator_LoopbackWorkers(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c := testCamera(t)
+	coordinator := NewCoordinator(11, 11, 4)
+	server := httptest.NewServer(coordinator)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := NewWorker(server.URL, c, w)
+			worker.SetPollInterval(10 * time.Millisecond)
+			if err := worker.Run(ctx); err != nil {
+				t.Errorf("Expected worker to finish, got: %s", err)
+			}
+		}()
+	}
+	image, err := coordinator.Wait(ctx)
+	wg.Wait()
+	if err != nil {
+		t.Errorf("Expected distributed render to finish, got: %s", err)
+		return
+	}
+	testCanvasEquals(t, flatten(image), flatten(c.Render(w)))
+}
+
+func TestCoordinator_Reassignment(t *testing.T) {
+	w := geometry.NewDefaultWorld()
+	c := testCamera(t)
+	coordinator := NewCoordinator(11, 11, 11)
+	coordinator.SetLease(20 * time.Millisecond)
+	server := httptest.NewServer(coordinator)
+	defer server.Close()
+
+	// a worker takes the only tile and dies
+	resp, err := http.Get(server.URL + "/job")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected a job to be handed out, got: %v", err)
+		return
+	}
+	resp.Body.Close()
+	resp, err = http.Get(server.URL + "/job")
+	if err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected no job while the only tile is leased")
+		return
+	}
+	resp.Body.Close()
+
+	time.Sleep(40 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	worker := NewWorker(server.URL, c, w)
+	if err := worker.Run(ctx); err != nil {
+		t.Errorf("Expected worker to finish, got: %s", err)
+	}
+	if _, err := coordinator.Wait(ctx); err != nil {
+		t.Errorf("Expected the expired tile to be rendered by the second worker, got: %s", err)
+	}
+}
+
+func TestCoordinator_TileFailed(t *testing.T) {
+	coordinator := NewCoordinator(4, 4, 4)
+	coordinator.SetAttempts(2)
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if tile, _ := coordinator.nextTile(start.Add(time.Duration(i) * 3 * DEFAULTLEASE)); tile == nil {
+			t.Errorf("Expected the tile to be handed out %d times", i+1)
+		}
+	}
+	if _, status := coordinator.nextTile(start.Add(6 * DEFAULTLEASE)); status != http.StatusGone {
+		t.Errorf("Expected the render to be given up, got status %d", status)
+	}
+	_, err := coordinator.Wait(context.Background())
+	var failed TileFailed
+	if !errors.As(err, &failed) || failed.Attempts != 2 {
+		t.Errorf("Expected a TileFailed error after 2 attempts, got: %v", err)
+	}
+}
+
+func TestCoordinator_WaitReclaimsLeases(t *testing.T) {
+	coordinator := NewCoordinator(4, 4, 4)
+	coordinator.SetLease(10 * time.Millisecond)
+	coordinator.SetAttempts(1)
+	// the only worker takes the tile and dies, no other worker ever asks for one
+	if tile, _ := coordinator.nextTile(time.Now()); tile == nil {
+		t.Errorf("Expected the tile to be handed out")
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := coordinator.Wait(ctx)
+	var failed TileFailed
+	if !errors.As(err, &failed) || failed.Attempts != 1 {
+		t.Errorf("Expected Wait to fail with a TileFailed error once the lease expired, got: %v", err)
+	}
+}
+
+//status returns the status of a GET request to url
+func status(t *testing.T, url string) int {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestCoordinator_WaitTellsWorkers(t *testing.T) {
+	coordinator := NewCoordinator(4, 4, 4)
+	server := httptest.NewServer(coordinator)
+	defer server.Close()
+	if status(t, server.URL+"/job?worker=a") != http.StatusOK || status(t, server.URL+"/job?worker=b") !=
+		http.StatusNoContent {
+		t.Fatalf("Expected worker a to take the only tile and worker b to wait")
+	}
+	body, _ := json.Marshal(Result{Tile: camera.Tile{X: 0, Y: 0, Width: 4, Height: 4}, Pixels: make([]float64, 48)})
+	resp, err := http.Post(server.URL+"/result?worker=a", "application/json", bytes.NewReader(body))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the result to be taken")
+	}
+	resp.Body.Close()
+
+	returned := make(chan struct{})
+	go func() {
+		coordinator.Wait(context.Background())
+		close(returned)
+	}()
+	// the image is done but Wait returns only once both workers were told so
+	for _, worker := range []string{"a", "b"} {
+		select {
+		case <-returned:
+			t.Fatalf("Expected Wait to wait for worker %s to be told the render is over", worker)
+		case <-time.After(20 * time.Millisecond):
+		}
+		if s := status(t, server.URL+"/job?worker="+worker); s != http.StatusGone {
+			t.Fatalf("Expected worker %s to be told the render is over, got status %d", worker, s)
+		}
+	}
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected Wait to return once every worker was told the render is over")
+	}
+
+	// a worker that is not heard from for the length of a lease is taken for dead
+	coordinator = NewCoordinator(4, 4, 4)
+	coordinator.SetLease(20 * time.Millisecond)
+	coordinator.checkIn("dead", time.Now(), false)
+	coordinator.storeResult(Result{Tile: camera.Tile{X: 0, Y: 0, Width: 4, Height: 4}, Pixels: make([]float64, 48)})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := coordinator.Wait(ctx); err != nil {
+		t.Errorf("Expected Wait to give up on the dead worker, got: %s", err)
+	}
+}
+
+func TestCoordinator_InvalidResult(t *testing.T) {
+	coordinator := NewCoordinator(4, 4, 2)
+	server := httptest.NewServer(coordinator)
+	defer server.Close()
+	body, _ := json.Marshal(Result{Tile: camera.Tile{X: 1, Y: 0, Width: 2, Height: 2}, Pixels: make([]float64, 12)})
+	resp, err := http.Post(server.URL+"/result", "application/json", bytes.NewReader(body))
+	if err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a result for an unknown tile to be rejected")
+	}
+	resp.Body.Close()
+	body, _ = json.Marshal(Result{Tile: camera.Tile{X: 2, Y: 0, Width: 2, Height: 2}, Pixels: make([]float64, 3)})
+	resp, err = http.Post(server.URL+"/result", "application/json", bytes.NewReader(body))
+	if err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a result with missing pixels to be rejected")
+	}
+	resp.Body.Close()
+}
+
+func TestWorker_MismatchedImage(t *testing.T) {
+	coordinator := NewCoordinator(20, 10, 4)
+	server := httptest.NewServer(coordinator)
+	defer server.Close()
+	worker := NewWorker(server.URL, testCamera(t), geometry.NewDefaultWorld())
+	err := worker.Run(context.Background())
+	var mismatched MismatchedImage
+	if !errors.As(err, &mismatched) {
+		t.Errorf("Expected a MismatchedImage error, got: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package distributed
+
+import (
+	"fmt"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/camera"
+)
+
+//TileFailed is the error returned by a Coordinator when a tile was handed out too many times without a result
+type TileFailed struct {
+	Tile     camera.Tile
+	Attempts int
+}
+
+func (e TileFailed) Error() string {
+	return fmt.Sprintf("Tile (%d, %d) was not rendered after %d attempts", e.Tile.X, e.Tile.Y, e.Attempts)
+}
+
+//InvalidResult is the error returned when a worker posts pixels that do not match a tile of the image
+type InvalidResult struct {
+	Tile   camera.Tile
+	Reason string
+}
+
+func (e InvalidResult) Error() string {
+	return fmt.Sprintf("Invalid result for tile (%d, %d): %s", e.Tile.X, e.Tile.Y, e.Reason)
+}
+
+//MismatchedImage is the error returned by a Worker whose camera does not render the image of the coordinator
+type MismatchedImage [4]int
+
+func (e MismatchedImage) Error() string {
+	return fmt.Sprintf("Coordinator renders a %dx%d image but the worker's camera renders %dx%d", e[0], e[1], e[2], e[3])
+}
+
+//UnexpectedStatus is the error returned by a Worker when the coordinator answers with an unexpected HTTP status
+type UnexpectedStatus int
+
+func (e UnexpectedStatus) Error() string {
+	return fmt.Sprintf("Unexpected response status from coordinator: %d", int(e))
+}
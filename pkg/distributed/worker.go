@@ -0,0 +1,177 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/camera"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+//workerCount numbers the workers of the process, so that their names are unique
+var workerCount int64
+
+//Worker renders the tiles handed out by a Coordinator with its own copy of the scene and posts their pixels back
+type Worker struct {
+	url     string // base URL of the coordinator
+	id      string // name of the worker sent to the coordinator, unique to the host, process and worker
+	camera  camera.Camera
+	world   *geometry.World
+	client  *http.Client
+	retries int           // number of times a failed request is retried before giving up
+	poll    time.Duration // wait between requests when there is no tile to render, and base of the retry backoff
+}
+
+//NewWorker returns a worker rendering the World through the camera c for the coordinator at url. c must be set up
+// exactly like the camera of the coordinator's render, its crop window, checkpoint and progress are ignored
+func NewWorker(url string, c *camera.Camera, w *geometry.World) *Worker {
+	cam := *c
+	cam.SetCheckpoint("")
+	cam.SetProgress(nil)
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+	id := fmt.Sprintf("%s-%d-%d", host, os.Getpid(), atomic.AddInt64(&workerCount, 1))
+	return &Worker{url: strings.TrimSuffix(url, "/"), id: id, camera: cam, world: w, client: http.DefaultClient,
+		retries: 3, poll: 500 * time.Millisecond}
+}
+
+//SetRetries sets the number of times a request to the coordinator is retried before the worker gives up
+func (wk *Worker) SetRetries(n int) {
+	wk.retries = n
+}
+
+//SetPollInterval sets how long the worker waits before asking again when every tile is leased to other workers
+func (wk *Worker) SetPollInterval(d time.Duration) {
+	wk.poll = d
+}
+
+//Run asks the coordinator for tiles and renders them until the image is done, ctx is cancelled or the coordinator
+// can no longer be reached
+func (wk *Worker) Run(ctx context.Context) error {
+	for {
+		var job Job
+		status, err := wk.request(ctx, func() (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, wk.endpoint("job"), nil)
+		}, &job)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case http.StatusGone:
+			return nil
+		case http.StatusNoContent:
+			if err := sleep(ctx, wk.poll); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, err := wk.render(ctx, job)
+		if err != nil {
+			return err
+		}
+		body, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		_, err = wk.request(ctx, func() (*http.Request, error) {
+			req, err := http.NewRequest(http.MethodPost, wk.endpoint("result"), bytes.NewReader(body))
+			if err == nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			return req, err
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+//endpoint returns the URL of the endpoint of the coordinator, naming the worker
+func (wk *Worker) endpoint(name string) string {
+	return wk.url + "/" + name + "?worker=" + url.QueryEscape(wk.id)
+}
+
+//render renders the tile of a job with the worker's camera restricted to the tile
+func (wk *Worker) render(ctx context.Context, job Job) (Result, error) {
+	if width, height := wk.camera.Size(); width != job.Width || height != job.Height {
+		return Result{}, MismatchedImage{job.Width, job.Height, width, height}
+	}
+	cam := wk.camera
+	t := job.Tile
+	cam.SetCropWindow(t.X, t.Y, t.Width, t.Height)
+	image, err := cam.RenderContext(ctx, wk.world)
+	if err != nil {
+		return Result{}, err
+	}
+	pixels := make([]float64, 0, 3*t.Width*t.Height)
+	for y := 0; y < image.Height; y++ {
+		for x := 0; x < image.Width; x++ {
+			color := image.Pixels[y][x]
+			pixels = append(pixels, color[0], color[1], color[2])
+		}
+	}
+	return Result{Tile: t, Pixels: pixels}, nil
+}
+
+//request sends the request built by newRequest and decodes a JSON answer into out when it is not nil. Network
+// errors and server errors are retried with a growing delay, the status of the answer is returned
+func (wk *Worker) request(ctx context.Context, newRequest func() (*http.Request, error), out interface{}) (int, error) {
+	var lastErr error
+	for attempt := 0; attempt <= wk.retries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, time.Duration(attempt)*wk.poll); err != nil {
+				return 0, err
+			}
+		}
+		req, err := newRequest()
+		if err != nil {
+			return 0, err
+		}
+		resp, err := wk.client.Do(req.WithContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		status := resp.StatusCode
+		if status >= 500 {
+			resp.Body.Close()
+			lastErr = UnexpectedStatus(status)
+			continue
+		}
+		if status != http.StatusOK && status != http.StatusNoContent && status != http.StatusGone {
+			resp.Body.Close()
+			return status, UnexpectedStatus(status)
+		}
+		if status == http.StatusOK && out != nil {
+			err = json.NewDecoder(resp.Body).Decode(out)
+		}
+		resp.Body.Close()
+		return status, err
+	}
+	return 0, lastErr
+}
+
+//sleep waits for d or until ctx is cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,60 @@
+package examples
+
+import (
+	"context"
+	camera2 "github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/camera"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/distributed"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry"
+	"log"
+	"net/http"
+	"time"
+)
+
+//CreateDistributedScene serves the tiles of a custom scene to workers on addr, waits for them to render every tile
+// and writes the result to ./pkg/examples/<name>.ppm. Workers are started with RunSceneWorker on the same scene
+func CreateDistributedScene(name, addr string) error {
+	cam, err := customSceneCamera()
+	if err != nil {
+		return err
+	}
+	width, height := cam.Size()
+	coordinator := distributed.NewCoordinator(width, height, camera2.DEFAULTTILESIZE)
+	server := &http.Server{Addr: addr, Handler: coordinator}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	log.Printf("Serving tiles on %s...", addr)
+
+	start := time.Now()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := <-serveErr; err != http.ErrServerClosed {
+			log.Println(err)
+			cancel()
+		}
+	}()
+	image, err := coordinator.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("Done (%s)!", time.Since(start))
+	// Wait returned once every worker was told the render is over, shutting down cannot cut one off
+	shutdown, stop := context.WithTimeout(context.Background(), 5*time.Second)
+	defer stop()
+	server.Shutdown(shutdown)
+
+	log.Println("Writing results to file ./pkg/examples/" + name + ".ppm...")
+	return writeToFile(image.ToPpmHeader(255)+image.ToPpmBody(255), name)
+}
+
+//RunSceneWorker renders tiles of a custom scene of the World for the coordinator at url until the image is done
+func RunSceneWorker(w *geometry.World, url string) error {
+	cam, err := customSceneCamera()
+	if err != nil {
+		return err
+	}
+	log.Printf("Rendering tiles for %s...", url)
+	return distributed.NewWorker(url, cam, w).Run(context.Background())
+}
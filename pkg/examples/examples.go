@@ -18,14 +18,15 @@ import (
 func CreateCustomScene(w *geometry.World, name string, rotate bool) error {
 	log.Println("Rendering scene...")
 	start := time.Now()
-	cam, err := camera2.NewCamera(1400, 1000, math.Pi/3,
-		algebra.ViewTransform(0, 30, -50,
-			0, 1, 0,
-			0, 1, 0))
+	cam, err := customSceneCamera()
 	if err != nil {
 		panic(err)
 		return err
 	}
+	cam.SetProgress(camera2.NewProgressBar(os.Stderr))
+	// finished tiles are saved next to the image so an interrupted render resumes where it stopped
+	checkpoint := "./pkg/examples/" + name + ".tiles"
+	cam.SetCheckpoint(checkpoint)
 	image := cam.Render(w)
 	t := time.Now()
 	elapsed := t.Sub(start)
@@ -38,12 +39,24 @@ func CreateCustomScene(w *geometry.World, name string, rotate bool) error {
 		panic(err)
 		return err
 	}
+	err = os.Remove(checkpoint)
+	if err != nil {
+		return err
+	}
 	t = time.Now()
 	elapsed = t.Sub(start)
 	log.Printf("Done (%s)!", elapsed)
 	return nil
 }
 
+//customSceneCamera returns the camera custom scenes are rendered with
+func customSceneCamera() (*camera2.Camera, error) {
+	return camera2.NewCamera(1400, 1000, math.Pi/3,
+		algebra.ViewTransform(0, 30, -50,
+			0, 1, 0,
+			0, 1, 0))
+}
+
 func writeToFile(toWrite, fileName string) error {
 	f, err := os.Create("./pkg/examples/" + fileName + ".ppm")
 	if err != nil {
@@ -99,7 +112,7 @@ func TestReflectiveScene() error {
 	s.SetMaterial(m)
 
 	light := canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-100, 0, -50))
-	lights := []*canvas.PointLight{light}
+	lights := []canvas.Light{light}
 	objs := []primitives.Shape{floor, s}
 	w := &geometry.World{Objects: objs, Lights: lights}
 	image := cam.Render(w)
@@ -172,7 +185,7 @@ func CreateSimpleReflectiveScene() error {
 
 	objs := make([]primitives.Shape, 0, 0)
 	objs = append(objs, floor, middle, middle2, lwall, rwall)
-	lights := make([]*canvas.PointLight, 0, 0)
+	lights := make([]canvas.Light, 0, 0)
 	lights = append(lights, canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-10, 10, -10)))
 	w := &geometry.World{Objects: objs, Lights: lights}
 
@@ -197,7 +210,7 @@ func CreateSimpleReflectiveScene() error {
 
 func CreateRefractiveReflectiveScene() error {
 
-	lights := make([]*canvas.PointLight, 0, 0)
+	lights := make([]canvas.Light, 0, 0)
 	objs := make([]primitives.Shape, 0, 0)
 
 	lights = append(lights, canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-2.5, 2.5, -4)))
@@ -344,8 +357,9 @@ func CreateSimpleScene2() error {
 
 	objs := make([]primitives.Shape, 0, 0)
 	objs = append(objs, floor, leftWall, rightWall, middle, left, right)
-	lights := make([]*canvas.PointLight, 0, 0)
-	lights = append(lights, canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-10, 10, -10)))
+	lights := make([]canvas.Light, 0, 0)
+	lights = append(lights, canvas.NewRectangleLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-11, 10, -11),
+		algebra.NewVector(2, 0, 0), 4, algebra.NewVector(0, 2, 0), 4, true))
 	w := &geometry.World{Objects: objs, Lights: lights}
 
 	cam, err := camera2.NewCamera(100, 50, math.Pi/3,
@@ -408,7 +422,7 @@ func CreateSimpleScene() error {
 
 	objs := make([]primitives.Shape, 0, 0)
 	objs = append(objs, middle)
-	lights := make([]*canvas.PointLight, 0, 0)
+	lights := make([]canvas.Light, 0, 0)
 	lights = append(lights, canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-10, 10, -10)))
 	w := &geometry.World{Objects: objs, Lights: lights}
 
@@ -522,3 +536,59 @@ func CreateProjectileExample() error {
 	}
 	return nil
 }
+
+//CreateProjectileMotionBlurExample renders the flight of the projectile simulation as a sphere keyframed at every
+// tick, the shutter stays open for a few ticks so the sphere is blurred along its trajectory
+func CreateProjectileMotionBlurExample() error {
+	startVelocity, err := algebra.NewVector(1, 1.8, 0.0).Normalize()
+	if err != nil {
+		return err
+	}
+	startVelocity = startVelocity.MultScalar(11.25)
+	p := &projectile{position: algebra.NewPoint(0, 1, 0), velocity: startVelocity}
+	e := &environment{gravity: algebra.NewVector(0, -0.1, 0), wind: algebra.NewVector(-0.01, 0, 0)}
+
+	// the simulation runs in pixel units, scale it down to the size of the scene
+	scale := 0.05
+	keyframes := make([]primitives.Keyframe, 0, 0)
+	for i := 0; p.position.Get()[1] > 0; i++ {
+		x, y := p.position.Get()[0]*scale, p.position.Get()[1]*scale
+		keyframes = append(keyframes, primitives.Keyframe{Time: float64(i),
+			Transform: algebra.Multiply(algebra.TranslationMatrix(x, y, 0), algebra.ScalingMatrix(0.5, 0.5, 0.5))})
+		err := tick(e, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	ball := primitives.NewSphere(nil)
+	ball.SetMotion(primitives.NewKeyframedMotion(keyframes...))
+	m := canvas.NewDefaultMaterial()
+	m.Color = &canvas.Color{1, 0.2, 0.1}
+	ball.SetMaterial(m)
+
+	floor := primitives.NewPlane(nil)
+	m = canvas.NewDefaultMaterial()
+	m.Pattern = canvas.CheckerPattern(&canvas.Color{0.9, 0.9, 0.9}, &canvas.Color{0.1, 0.1, 0.1})
+	m.Specular = 0
+	floor.SetMaterial(m)
+
+	lights := []canvas.Light{canvas.NewDirectionalLight(&canvas.Color{1, 1, 1}, algebra.NewVector(2, -4, 3))}
+	w := &geometry.World{Objects: []primitives.Shape{floor, ball}, Lights: lights}
+
+	cam, err := camera2.NewCamera(800, 500, math.Pi/3,
+		algebra.ViewTransform(25, 8, -40,
+			25, 8, 0,
+			0, 1, 0))
+	if err != nil {
+		return err
+	}
+	cam.SetSampler(camera2.NewSampler(16, camera2.JitteredSampling, nil, 0))
+	cam.SetShutter(30, 36)
+	image := cam.Render(w)
+	err = writeToFile(image.ToPpmHeader(255)+image.ToPpmBody(255), "projectileMotionBlur")
+	if err != nil {
+		return err
+	}
+	return nil
+}
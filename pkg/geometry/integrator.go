@@ -0,0 +1,25 @@
+package geometry
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"math/rand"
+)
+
+//Integrator computes the color of the light coming back along a camera ray
+type Integrator interface {
+	//Li returns the color seen along the ray r in the World, rng gives the random numbers of the pixel the ray was
+	// cast for so renders are reproducible
+	Li(w *World, r *algebra.Ray, rng *rand.Rand) *canvas.Color
+}
+
+//WhittedIntegrator traces reflected and refracted rays recursively up to MaxDepth times with World.ColorAt. It is
+// deterministic and ignores the random numbers
+type WhittedIntegrator struct {
+	MaxDepth int
+}
+
+//Li returns the color seen along the ray r with World.ColorAt
+func (i WhittedIntegrator) Li(w *World, r *algebra.Ray, rng *rand.Rand) *canvas.Color {
+	return w.ColorAt(r, i.MaxDepth)
+}
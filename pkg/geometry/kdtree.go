@@ -0,0 +1,127 @@
+package geometry
+
+import (
+	"container/heap"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"math"
+	"sort"
+)
+
+//Photon is a packet of light stored where it landed on a surface
+type Photon struct {
+	Position  *algebra.Vector
+	Direction *algebra.Vector // unit vector the photon travelled along before landing
+	Power     *canvas.Color
+}
+
+//kdTree is a balanced kd-tree of photons. It is stored in a single array: the photon splitting a range of the array
+// is at its middle, the photons before it are below it along the split axis and the photons after it above
+type kdTree struct {
+	photons []Photon
+	axes    []int // axis split by the photon at the same index
+}
+
+//newKDTree returns a balanced kd-tree holding the photons, which are reordered
+func newKDTree(photons []Photon) *kdTree {
+	t := &kdTree{photons: photons, axes: make([]int, len(photons))}
+	t.build(0, len(photons))
+	return t
+}
+
+//build splits the photons in [lo, hi) around the median along the axis they are the most spread over
+func (t *kdTree) build(lo, hi int) {
+	if hi-lo < 1 {
+		return
+	}
+	low := []float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	high := []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, p := range t.photons[lo:hi] {
+		for i := 0; i < 3; i++ {
+			low[i] = math.Min(low[i], p.Position.Get()[i])
+			high[i] = math.Max(high[i], p.Position.Get()[i])
+		}
+	}
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if high[i]-low[i] > high[axis]-low[axis] {
+			axis = i
+		}
+	}
+	part := t.photons[lo:hi]
+	sort.Slice(part, func(i, j int) bool { return part[i].Position.Get()[axis] < part[j].Position.Get()[axis] })
+	mid := (lo + hi) / 2
+	t.axes[mid] = axis
+	t.build(lo, mid)
+	t.build(mid+1, hi)
+}
+
+//neighbour is a photon found by a search along with its squared distance to the searched point
+type neighbour struct {
+	photon *Photon
+	dist2  float64
+}
+
+//neighbours is a max-heap of the photons found so far, the farthest one on top
+type neighbours []neighbour
+
+func (h neighbours) Len() int            { return len(h) }
+func (h neighbours) Less(i, j int) bool  { return h[i].dist2 > h[j].dist2 }
+func (h neighbours) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighbours) Push(x interface{}) { *h = append(*h, x.(neighbour)) }
+func (h *neighbours) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+//nearest returns the k photons closest to the point p that are at most maxDist away from it, in no particular order
+func (t *kdTree) nearest(p *algebra.Vector, k int, maxDist float64) neighbours {
+	found := make(neighbours, 0, k)
+	if t == nil || k < 1 {
+		return found
+	}
+	maxDist2 := maxDist * maxDist
+	t.search(0, len(t.photons), p.Get()[:3], k, &maxDist2, &found)
+	return found
+}
+
+//search looks for the photons of [lo, hi) closer to p than maxDist2, which shrinks once k photons are found
+func (t *kdTree) search(lo, hi int, p []float64, k int, maxDist2 *float64, found *neighbours) {
+	if hi-lo < 1 {
+		return
+	}
+	mid := (lo + hi) / 2
+	photon := &t.photons[mid]
+	diff := p[t.axes[mid]] - photon.Position.Get()[t.axes[mid]]
+	// the side of the split p is on is searched first so maxDist2 shrinks early
+	if diff < 0 {
+		t.search(lo, mid, p, k, maxDist2, found)
+	} else {
+		t.search(mid+1, hi, p, k, maxDist2, found)
+	}
+
+	dist2 := 0.0
+	for i := 0; i < 3; i++ {
+		d := p[i] - photon.Position.Get()[i]
+		dist2 += d * d
+	}
+	if dist2 <= *maxDist2 {
+		heap.Push(found, neighbour{photon: photon, dist2: dist2})
+		if found.Len() > k {
+			heap.Pop(found)
+		}
+		if found.Len() == k {
+			*maxDist2 = (*found)[0].dist2
+		}
+	}
+
+	if diff*diff <= *maxDist2 {
+		if diff < 0 {
+			t.search(mid+1, hi, p, k, maxDist2, found)
+		} else {
+			t.search(lo, mid, p, k, maxDist2, found)
+		}
+	}
+}
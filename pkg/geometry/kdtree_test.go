@@ -0,0 +1,60 @@
+package geometry
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestKDTree_nearest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	photons := make([]Photon, 0, 500)
+	for i := 0; i < 500; i++ {
+		photons = append(photons, Photon{Position: algebra.NewPoint(rng.Float64(), rng.Float64(), rng.Float64()),
+			Direction: algebra.NewVector(0, -1, 0), Power: &canvas.Color{1, 1, 1}})
+	}
+	points := make([]*algebra.Vector, 0, len(photons))
+	for _, p := range photons {
+		points = append(points, p.Position)
+	}
+	tree := newKDTree(photons)
+
+	for _, p := range []*algebra.Vector{algebra.NewPoint(0.5, 0.5, 0.5), algebra.NewPoint(0, 0.2, 1),
+		algebra.NewPoint(2, 2, 2)} {
+		// brute force squared distances of the 10 closest points within 0.3
+		expected := make([]float64, 0, len(points))
+		for _, q := range points {
+			d, _ := q.Subtract(p)
+			if d2 := d.Magnitude() * d.Magnitude(); d2 <= 0.09 {
+				expected = append(expected, d2)
+			}
+		}
+		sort.Float64s(expected)
+		if len(expected) > 10 {
+			expected = expected[:10]
+		}
+
+		found := tree.nearest(p, 10, 0.3)
+		got := make([]float64, 0, len(found))
+		for _, n := range found {
+			got = append(got, n.dist2)
+		}
+		sort.Float64s(got)
+		if len(got) != len(expected) {
+			t.Errorf("Expected %d photons around %v, got %d", len(expected), p.Get(), len(got))
+			continue
+		}
+		for i := range got {
+			if !equals(got[i], expected[i]) {
+				t.Errorf("Expected squared distance %f, got %f", expected[i], got[i])
+			}
+		}
+	}
+
+	var empty *kdTree
+	if len(empty.nearest(algebra.NewPoint(0, 0, 0), 10, 1)) != 0 {
+		t.Errorf("Expected no photons in an empty tree")
+	}
+}
@@ -44,6 +44,7 @@ type CSGShape struct {
 	Parent    primitives.Shape
 	material  *canvas.Material
 	transform *algebra.Matrix
+	motion    *primitives.Motion
 	left      primitives.Shape
 	right     primitives.Shape
 	action    string
@@ -112,6 +113,17 @@ func (s *CSGShape) GetTransform() *algebra.Matrix {
 	return s.transform
 }
 
+//SetMotion Setter for the CSGShape's motion over the shutter interval, nil makes it static
+func (s *CSGShape) SetMotion(m *primitives.Motion) {
+	s.motion = m
+	primitives.RefreshBounds(s)
+}
+
+//GetMotion Getter for the CSGShape's motion, nil when it is static
+func (s *CSGShape) GetMotion() *primitives.Motion {
+	return s.motion
+}
+
 //GetMaterial Getter for CSG Shape material, primitives.Shape interface method
 func (s *CSGShape) GetMaterial() *canvas.Material {
 	return s.material
@@ -127,8 +139,8 @@ func (s *CSGShape) GetBounds() (*algebra.Vector, *algebra.Vector) {
 
 	minL, maxL := s.left.GetBounds()
 	minR, maxR := s.right.GetBounds()
-	bL := primitives.GetBoundsTransform(minL, maxL, s.left.GetTransform())
-	br := primitives.GetBoundsTransform(minL, maxL, s.right.GetTransform())
+	bL := primitives.MotionBounds(s.left, minL, maxL)
+	br := primitives.MotionBounds(s.right, minR, maxR)
 	minL, maxL = bL.Get()
 	minR, maxR = br.Get()
 
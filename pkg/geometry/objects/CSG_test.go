@@ -164,6 +164,14 @@ func TestCSGShape_GetBounds(t *testing.T) {
 	min, max := csg.GetBounds()
 	testVectorEquals(t, min.Get(), algebra.NewVector(-1, -2, -2).Get())
 	testVectorEquals(t, max.Get(), algebra.NewVector(4, 2, 2).Get())
+
+	// the bounds contain the children over their whole motion
+	s3 := primitives.NewSphere(nil)
+	s3.SetMotion(primitives.NewMotion(algebra.TranslationMatrix(0, 0, 0), algebra.TranslationMatrix(0, 5, 0)))
+	csg = UnionCSG(s, s3)
+	min, max = csg.GetBounds()
+	testVectorEquals(t, min.Get(), algebra.NewVector(-1, -1, -1).Get())
+	testVectorEquals(t, max.Get(), algebra.NewVector(1, 6, 1).Get())
 }
 
 func TestCSGShape_GetMaterial(t *testing.T) {
@@ -0,0 +1,85 @@
+package geometry
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"math"
+	"math/rand"
+)
+
+//OCCLUSIONRADIUS is the length of the occlusion rays of an OcclusionIntegrator without AmbientOcclusion settings
+var OCCLUSIONRADIUS float64 = 1
+
+//OCCLUSIONSAMPLES is the number of occlusion rays of an OcclusionIntegrator without AmbientOcclusion settings
+var OCCLUSIONSAMPLES int = 16
+
+//AmbientOcclusion estimates how much of the hemisphere above a hit is hidden by nearby objects, which darkens the
+// ambient term in the creases, corners and contact areas the ambient light hardly reaches
+type AmbientOcclusion struct {
+	Radius  float64 // objects further than Radius from the hit do not occlude it
+	Samples int     // number of rays cast over the hemisphere at each hit
+}
+
+//NewAmbientOcclusion returns a new AmbientOcclusion casting samples rays of length radius
+func NewAmbientOcclusion(radius float64, samples int) *AmbientOcclusion {
+	return &AmbientOcclusion{Radius: radius, Samples: samples}
+}
+
+//accessibility returns the fraction of the cosine weighted rays cast from the OverPoint of the hit that travel
+// Radius without hitting an object, from 0 in a closed crease to 1 in the open. The rays follow a stratified pattern
+// shifted by the numbers du and dv in [0, 1)
+func (a *AmbientOcclusion) accessibility(w *World, comps *Comps, du, dv float64) float64 {
+	if a.Samples <= 0 || a.Radius <= 0 {
+		return 1
+	}
+	open := 0
+	for i := 0; i < a.Samples; i++ {
+		// a Fibonacci lattice spreads the samples evenly over the hemisphere
+		u := math.Mod((float64(i)+0.5)/float64(a.Samples)+du, 1)
+		v := math.Mod(float64(i)*(math.Sqrt(5)-1)/2+dv, 1)
+		r := newRay(comps.OverPoint, cosineDirectionAt(comps.Normal, u, v))
+		r.SetTime(comps.Time)
+		if h := w.Intersect(r).Hit(); h == nil || h.T >= a.Radius {
+			open++
+		}
+	}
+	return float64(open) / float64(a.Samples)
+}
+
+//pointOffsets returns two numbers in [0, 1) hashed from the coordinates of the point, so neighbouring hits shift
+// their samples differently without sharing a random number generator
+func pointOffsets(p *algebra.Vector) (float64, float64) {
+	h := uint64(0x9e3779b97f4a7c15)
+	for _, c := range p.Get()[:3] {
+		h ^= math.Float64bits(c)
+		// splitmix64 finalizer
+		h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+		h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+		h ^= h >> 31
+	}
+	return float64(h>>40) / (1 << 24), float64(h&(1<<24-1)) / (1 << 24)
+}
+
+//OcclusionIntegrator renders the ambient occlusion of the World as a greyscale image, white where the hemisphere
+// above the first hit is open and black where it is hidden. Rays that miss every object are white. It ignores the
+// lights and materials, which makes it a quick preview of the geometry and a pass to composite. A nil Occlusion
+// casts OCCLUSIONSAMPLES rays of length OCCLUSIONRADIUS
+type OcclusionIntegrator struct {
+	Occlusion *AmbientOcclusion
+}
+
+//Li returns the accessibility of the first hit of the ray r as a grey color
+func (i OcclusionIntegrator) Li(w *World, r *algebra.Ray, rng *rand.Rand) *canvas.Color {
+	is := w.Intersect(r)
+	h := is.Hit()
+	if h == nil {
+		return &canvas.Color{1, 1, 1}
+	}
+	comps := PrepareComputations(h, r, is)
+	occlusion := i.Occlusion
+	if occlusion == nil {
+		occlusion = NewAmbientOcclusion(OCCLUSIONRADIUS, OCCLUSIONSAMPLES)
+	}
+	a := occlusion.accessibility(w, comps, rng.Float64(), rng.Float64())
+	return &canvas.Color{a, a, a}
+}
@@ -0,0 +1,94 @@
+package geometry
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry/primitives"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+//floorAndWall returns a World of a floor at y = 0 and a wall at x = 1
+func floorAndWall() *World {
+	floor := primitives.NewPlane(nil)
+	wall := primitives.NewPlane(algebra.Multiply(algebra.TranslationMatrix(1, 0, 0),
+		algebra.RotationZ(math.Pi/2)))
+	return &World{Objects: []primitives.Shape{floor, wall}}
+}
+
+func TestAmbientOcclusion_accessibility(t *testing.T) {
+	w := floorAndWall()
+	a := NewAmbientOcclusion(0.5, 64)
+	accessibility := func(x float64) float64 {
+		r := algebra.NewRay(x, 1, 0, 0, -1, 0)
+		is := w.Intersect(r)
+		return a.accessibility(w, PrepareComputations(is.Hit(), r, is), 0.3, 0.7)
+	}
+	// the wall is out of reach far from the corner
+	if got := accessibility(-1); !equals(got, 1) {
+		t.Errorf("Expected an open floor, got %f", got)
+	}
+	// the wall hides part of the hemisphere near the corner, more so closer to it
+	near, nearer := accessibility(0.8), accessibility(0.98)
+	if near >= 1 || nearer >= near || nearer <= 0 {
+		t.Errorf("Expected the accessibility to drop towards the corner, got %f then %f", near, nearer)
+	}
+	if got := NewAmbientOcclusion(0.5, 0); got.Samples != 0 || !equals(got.accessibility(w, nil, 0, 0), 1) {
+		t.Errorf("Expected no occlusion without samples")
+	}
+}
+
+func TestWorld_ShadeHitOcclusion(t *testing.T) {
+	floor := primitives.NewPlane(nil)
+	ceiling := primitives.NewPlane(algebra.TranslationMatrix(0, 1, 0))
+	w := &World{Objects: []primitives.Shape{floor, ceiling},
+		Lights: []canvas.Light{canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(0, 10, 0))}}
+
+	// the floor is in the shadow of the ceiling so only the ambient term is left
+	r := algebra.NewRay(0, 0.5, 0, 0, -1, 0)
+	testColorEquals(t, w.ColorAt(r, 0), &canvas.Color{0.1, 0.1, 0.1})
+	// the ceiling hides the whole hemisphere, the ambient term goes out
+	w.Occlusion = NewAmbientOcclusion(100, 16)
+	testColorEquals(t, w.ColorAt(r, 0), &canvas.Color{0, 0, 0})
+	// a ceiling out of reach does not occlude
+	w.Occlusion = NewAmbientOcclusion(0.5, 16)
+	testColorEquals(t, w.ColorAt(r, 0), &canvas.Color{0.1, 0.1, 0.1})
+}
+
+func TestOcclusionIntegrator_Li(t *testing.T) {
+	w := floorAndWall()
+	i := OcclusionIntegrator{Occlusion: NewAmbientOcclusion(0.5, 32)}
+	rng := rand.New(rand.NewSource(1))
+
+	testColorEquals(t, i.Li(w, algebra.NewRay(0, 1, 0, 0, 1, 0), rng), &canvas.Color{1, 1, 1})
+	testColorEquals(t, i.Li(w, algebra.NewRay(-1, 1, 0, 0, -1, 0), rng), &canvas.Color{1, 1, 1})
+	corner := i.Li(w, algebra.NewRay(0.95, 1, 0, 0, -1, 0), rng)
+	if corner.Red() >= 1 || corner.Red() <= 0 || corner.Red() != corner.Green() || corner.Red() != corner.Blue() {
+		t.Errorf("Expected a grey corner, got %v", corner)
+	}
+
+	// without settings the integrator falls back to the default occlusion instead of failing
+	rng = rand.New(rand.NewSource(1))
+	got := OcclusionIntegrator{}.Li(w, algebra.NewRay(0.95, 1, 0, 0, -1, 0), rng)
+	rng = rand.New(rand.NewSource(1))
+	defaults := OcclusionIntegrator{Occlusion: NewAmbientOcclusion(OCCLUSIONRADIUS, OCCLUSIONSAMPLES)}
+	testColorEquals(t, got, defaults.Li(w, algebra.NewRay(0.95, 1, 0, 0, -1, 0), rng))
+	if got.Red() >= 1 || got.Red() <= 0 {
+		t.Errorf("Expected a grey corner without occlusion settings, got %v", got)
+	}
+}
+
+func TestPointOffsets(t *testing.T) {
+	p := algebra.NewPoint(0.1, 2, -3)
+	u, v := pointOffsets(p)
+	if u < 0 || u >= 1 || v < 0 || v >= 1 {
+		t.Errorf("Expected offsets in [0, 1), got %f and %f", u, v)
+	}
+	if u2, v2 := pointOffsets(algebra.NewPoint(0.1, 2, -3)); u2 != u || v2 != v {
+		t.Errorf("Expected the same offsets for the same point")
+	}
+	if u2, _ := pointOffsets(algebra.NewPoint(0.1, 2, -3.001)); u2 == u {
+		t.Errorf("Expected different offsets for different points")
+	}
+}
@@ -0,0 +1,219 @@
+package geometry
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry/primitives"
+	"math"
+	"math/rand"
+)
+
+//ROULETTEDEPTH is the number of bounces after which paths may be ended by Russian roulette
+var ROULETTEDEPTH int = 3
+
+//PathTracer is a Monte Carlo Integrator that follows a single random path of light per camera ray, so light bounces
+// between diffuse surfaces and colors bleed onto their neighbours. At every hit the light of the World's lights is
+// gathered directly (next-event estimation) and the path goes on in a direction picked at random from the material:
+// cosine weighted over the hemisphere for its Diffuse part, mirrored for its Reflective part and refracted for its
+// Transparency. A World Background that can be sampled, such as an EnvironmentMap, is gathered as a light too.
+// Diffuse surfaces are Lambertian, reflecting albedo / pi of the light reaching them, without Phong highlights.
+// Single paths are noisy, pair it with a camera Sampler so each pixel averages many of them
+type PathTracer struct {
+	MaxDepth      int // maximum number of bounces of a path
+	RouletteDepth int // bounces after which a path is ended at random with a probability growing as it gets darker
+}
+
+//NewPathTracer returns a new PathTracer following paths of at most maxDepth bounces, Russian roulette starts after
+// ROULETTEDEPTH bounces
+func NewPathTracer(maxDepth int) *PathTracer {
+	return &PathTracer{MaxDepth: maxDepth, RouletteDepth: ROULETTEDEPTH}
+}
+
+//Li returns an estimate of the color seen along the ray r, averaging the estimates of many rays converges to the
+// light coming back along them
+func (pt PathTracer) Li(w *World, r *algebra.Ray, rng *rand.Rand) *canvas.Color {
+	color := &canvas.Color{0, 0, 0}
+	throughput := &canvas.Color{1, 1, 1}
+	ray := r
+	specular := true // the path reached the hit from the camera or a mirror, emission was not gathered already
+	for depth := 0; ; depth++ {
+		is := w.Intersect(ray)
+		hit := is.Hit()
+		if hit == nil {
+			// a sampled background was already gathered as a light at the previous diffuse hit
+			if _, sampled := w.Background.(canvas.SampledBackground); specular || !sampled {
+				color = color.Add(canvas.Multiply(throughput, w.background(ray)))
+			}
+			break
+		}
+		comps := PrepareComputations(hit, ray, is)
+		material := comps.Object.GetMaterial()
+		albedo := material.Color
+		if material.Pattern != nil {
+			albedo = primitives.PatternAtObjectTime(comps.Object, material.Pattern, comps.Point, comps.Time)
+		}
+
+		// emissive surfaces sampled as lights were already gathered at the previous diffuse hit
+		if material.IsEmissive() && (specular || !w.isLightSource(comps.Object)) {
+			color = color.Add(canvas.Multiply(throughput, material.Emitted()))
+		}
+		// bounced light replaces the ambient term
+		color = color.Add(canvas.Multiply(throughput, w.directLighting(comps, material, albedo)))
+		color = color.Add(canvas.Multiply(throughput, w.backgroundLighting(comps, material, albedo, rng)))
+		if depth >= pt.MaxDepth {
+			break
+		}
+
+		next, weight, isSpecular := scatter(comps, material, albedo, rng)
+		if next == nil {
+			break
+		}
+		throughput = canvas.Multiply(throughput, weight)
+		specular = isSpecular
+		if depth >= pt.RouletteDepth {
+			survival := math.Min(math.Max(throughput[0], math.Max(throughput[1], throughput[2])), 0.95)
+			if rng.Float64() >= survival {
+				break
+			}
+			throughput = throughput.ScalarMult(1 / survival)
+		}
+		next.SetTime(comps.Time)
+		ray = next
+	}
+	return color
+}
+
+//scatter picks the ray a path goes on along after the hit described by comps and returns it with the factor the
+// light it brings back is multiplied by, and whether it was a mirror or refraction bounce. The Diffuse, Reflective
+// and Transparency parts of the material are picked in proportion to their share of the light, reflection and
+// refraction being split by the Schlick reflectance like World.ShadeHit does. A nil ray ends the path
+func scatter(comps *Comps, material *canvas.Material, albedo *canvas.Color, rng *rand.Rand) (*algebra.Ray, *canvas.Color, bool) {
+	diffuse, reflective, transparency := math.Max(material.Diffuse, 0), material.Reflective, material.Transparency
+	if reflective > 0 && transparency > 0 {
+		reflectance := Schlick(comps)
+		reflective *= reflectance
+		transparency *= 1 - reflectance
+	}
+	total := diffuse + reflective + transparency
+	if total <= 0 {
+		return nil, nil, false
+	}
+	weight := &canvas.Color{total, total, total}
+	choice := rng.Float64() * total
+	switch {
+	case choice < reflective:
+		return newRay(comps.OverPoint, comps.Reflect), weight, true
+	case choice < reflective+transparency:
+		direction := refractedDirection(comps)
+		if direction == nil {
+			return nil, nil, false
+		}
+		return newRay(comps.UnderPoint, direction), weight, true
+	default:
+		// the cosine weighted direction cancels the cosine and 1/pi of a Lambertian surface
+		return newRay(comps.OverPoint, cosineDirection(comps.Normal, rng)), canvas.Multiply(weight, albedo), false
+	}
+}
+
+//directLighting returns the light of the World's lights reflected by the diffuse part of the material. Like for the
+// bounces and the background, a Lambertian surface reflects albedo / pi of the light reaching it, each light lighting
+// the hit as much as it is visible from it
+func (w World) directLighting(comps *Comps, material *canvas.Material, albedo *canvas.Color) *canvas.Color {
+	irradiance := &canvas.Color{0, 0, 0}
+	if material.Diffuse <= 0 {
+		return irradiance
+	}
+	for _, l := range w.Lights {
+		samples, weights := canvas.LightSamples(l, comps.OverPoint)
+		if len(samples) == 0 {
+			continue
+		}
+		sum := &canvas.Color{0, 0, 0}
+		for k, sample := range samples {
+			direction, dist := l.DirectionFrom(comps.Point, sample)
+			cos, err := algebra.DotProduct(direction, comps.Normal)
+			if err != nil {
+				panic(err)
+			}
+			if cos <= 0 {
+				continue
+			}
+			weight := &canvas.Color{1, 1, 1}
+			if weights != nil {
+				weight = weights[k]
+			}
+			sum = sum.Add(weight.ScalarMult(cos * l.GetFalloff().Attenuation(dist)))
+		}
+		if sum[0] <= 0 && sum[1] <= 0 && sum[2] <= 0 {
+			continue
+		}
+		visibility := w.lightVisibilityAt(comps.OverPoint, l, samples, weights, comps.Time)
+		light := canvas.Multiply(l.IntensityAt(comps.Point), visibility)
+		irradiance = irradiance.Add(canvas.Multiply(light, sum).ScalarMult(1 / float64(len(samples))))
+	}
+	return canvas.Multiply(albedo, irradiance).ScalarMult(material.Diffuse / math.Pi)
+}
+
+//backgroundLighting returns the light of the World's Background reflected by the diffuse part of the material when
+// the background is a canvas.SampledBackground, estimated from a single direction sampled from it
+func (w World) backgroundLighting(comps *Comps, material *canvas.Material, albedo *canvas.Color, rng *rand.Rand) *canvas.Color {
+	background, ok := w.Background.(canvas.SampledBackground)
+	if !ok || material.Diffuse <= 0 {
+		return &canvas.Color{0, 0, 0}
+	}
+	direction, light, pdf := background.Sample(rng.Float64(), rng.Float64())
+	cos, err := algebra.DotProduct(direction, comps.Normal)
+	if err != nil {
+		panic(err)
+	}
+	if pdf <= 0 || cos <= 0 {
+		return &canvas.Color{0, 0, 0}
+	}
+	shadowRay := newRay(comps.OverPoint, direction)
+	shadowRay.SetTime(comps.Time)
+	if w.Intersect(shadowRay).Hit() != nil {
+		return &canvas.Color{0, 0, 0}
+	}
+	// a Lambertian surface reflects albedo / pi of the light coming from each direction
+	return canvas.Multiply(albedo, light).ScalarMult(material.Diffuse * cos / (math.Pi * pdf))
+}
+
+//isLightSource returns whether the shape is one of the emissive surfaces of a primitives.GeometryLight of the World
+func (w World) isLightSource(s primitives.Shape) bool {
+	for _, l := range w.Lights {
+		if g, ok := l.(*primitives.GeometryLight); ok && g.Contains(s) {
+			return true
+		}
+	}
+	return false
+}
+
+//cosineDirection returns a random unit vector of the hemisphere around the normal, directions are picked with a
+// probability proportional to the cosine of their angle with the normal
+func cosineDirection(normal *algebra.Vector, rng *rand.Rand) *algebra.Vector {
+	return cosineDirectionAt(normal, rng.Float64(), rng.Float64())
+}
+
+//cosineDirectionAt returns the unit vector of the hemisphere around the normal mapped from the numbers u and v in
+// [0, 1), uniformly distributed numbers give cosine weighted directions
+func cosineDirectionAt(normal *algebra.Vector, u, v float64) *algebra.Vector {
+	x, y := algebra.OrthonormalBasis(normal)
+	r := math.Sqrt(u)
+	phi := 2 * math.Pi * v
+	z := math.Sqrt(math.Max(0, 1-r*r))
+	direction, err := x.MultScalar(r * math.Cos(phi)).Add(y.MultScalar(r * math.Sin(phi)))
+	if err != nil {
+		panic(err)
+	}
+	direction, err = direction.Add(normal.MultScalar(z))
+	if err != nil {
+		panic(err)
+	}
+	return direction
+}
+
+//newRay returns the ray starting at the point origin along direction
+func newRay(origin, direction *algebra.Vector) *algebra.Ray {
+	values := []float64{origin.Get()[0], origin.Get()[1], origin.Get()[2]}
+	return algebra.NewRay(append(values, direction.Get()[:3]...)...)
+}
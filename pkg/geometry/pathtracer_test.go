@@ -0,0 +1,165 @@
+package geometry
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry/primitives"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+//shadedRoom returns a floor and a ceiling lit by a point light between them, the floor under the light is shadowed
+// by a thin slab so it only receives the light bounced off the ceiling
+func shadedRoom() *World {
+	floor := primitives.NewPlane(nil)
+	ceiling := primitives.NewPlane(algebra.TranslationMatrix(0, 2, 0))
+	slab := primitives.NewCube(algebra.Multiply(algebra.TranslationMatrix(0, 0.5, 0),
+		algebra.ScalingMatrix(1, 0.01, 1)))
+	w := &World{Objects: []primitives.Shape{floor, ceiling, slab}}
+	w.Lights = []canvas.Light{canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(0, 1, 0))}
+	return w
+}
+
+func TestWhittedIntegrator_Li(t *testing.T) {
+	w := NewDefaultWorld()
+	r := algebra.NewRay(0, 0, -5, 0, 0, 1)
+	expected := w.ColorAt(r, 3)
+	if c := (WhittedIntegrator{MaxDepth: 3}).Li(w, r, nil); *c != *expected {
+		t.Errorf("Expected %v, got %v", expected, c)
+	}
+}
+
+func TestPathTracer_Li(t *testing.T) {
+	w := shadedRoom()
+	pt := NewPathTracer(5)
+	r := algebra.NewRay(0, 0.25, -1, 0, -0.25, 1)
+
+	// the same random numbers give the same path
+	a := pt.Li(w, r, rand.New(rand.NewSource(1)))
+	b := pt.Li(w, r, rand.New(rand.NewSource(1)))
+	if *a != *b {
+		t.Errorf("Expected the same seed to give the same color, got %v and %v", a, b)
+	}
+
+	// without bounces the shadowed floor is black, the ambient term is not used
+	direct := NewPathTracer(0).Li(w, r, rand.New(rand.NewSource(1)))
+	if *direct != (canvas.Color{0, 0, 0}) {
+		t.Errorf("Expected the shadowed floor to only be lit indirectly, got %v", direct)
+	}
+
+	// the light bounced off the ceiling reaches the floor
+	rng := rand.New(rand.NewSource(1))
+	sum := &canvas.Color{0, 0, 0}
+	n := 500
+	for i := 0; i < n; i++ {
+		sum = sum.Add(pt.Li(w, r, rng))
+	}
+	average := sum.ScalarMult(1 / float64(n))
+	if average.Red() <= 0.01 || math.Abs(average.Red()-average.Blue()) > 1e-9 {
+		t.Errorf("Expected the floor to be lit by the ceiling, got %v", average)
+	}
+}
+
+func TestPathTracer_LiEmissive(t *testing.T) {
+	lamp := primitives.NewSphere(nil)
+	m := canvas.NewDefaultMaterial()
+	m.Emission = &canvas.Color{1, 0.5, 0}
+	m.EmissionStrength = 2
+	lamp.SetMaterial(m)
+	w := &World{Objects: []primitives.Shape{lamp}}
+	r := algebra.NewRay(0, 0, -5, 0, 0, 1)
+	c := NewPathTracer(5).Li(w, r, rand.New(rand.NewSource(1)))
+	testColorEquals(t, c, &canvas.Color{2, 1, 0})
+
+	// the glow of a lamp sampled as a light is not gathered again by paths bouncing off the floor
+	floor := primitives.NewPlane(algebra.TranslationMatrix(0, -2, 0))
+	w = &World{Objects: []primitives.Shape{floor, lamp}}
+	light, err := primitives.NewGeometryLight(lamp, 16, false)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	w.Lights = []canvas.Light{light}
+	if !w.isLightSource(lamp) || w.isLightSource(floor) {
+		t.Errorf("Expected only the lamp to be a light source")
+	}
+}
+
+func TestPathTracer_LiGeometryLight(t *testing.T) {
+	floor := primitives.NewPlane(nil)
+	lamp := primitives.NewSphere(algebra.Multiply(algebra.TranslationMatrix(0, 3, 0), algebra.ScalingMatrix(2, 2, 2)))
+	m := canvas.NewDefaultMaterial()
+	m.Emission = &canvas.Color{1, 1, 1}
+	m.EmissionStrength = 1
+	lamp.SetMaterial(m)
+	// a single bounce off the floor under the lamp
+	r := algebra.NewRay(0, 1, -2, 0, -1, 2)
+	average := func(w *World, n int) float64 {
+		rng := rand.New(rand.NewSource(1))
+		sum := 0.0
+		for i := 0; i < n; i++ {
+			sum += NewPathTracer(1).Li(w, r, rng).Red()
+		}
+		return sum / float64(n)
+	}
+
+	// paths bouncing off the floor find the lamp by chance, its irradiance 4 pi / 9 is reflected as 0.9 * 4 / 9
+	found := average(&World{Objects: []primitives.Shape{floor, lamp}}, 2000)
+	// the same lamp sampled as a light gives the same light
+	light, err := primitives.NewGeometryLight(lamp, 16, false)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	sampled := average(&World{Objects: []primitives.Shape{floor, lamp}, Lights: []canvas.Light{light}}, 200)
+	if math.Abs(found-0.4) > 0.03 || math.Abs(sampled-0.4) > 0.01 {
+		t.Errorf("Expected the lamp found by paths and sampled as a light to both give 0.4, got %f and %f",
+			found, sampled)
+	}
+}
+
+func TestCosineDirection(t *testing.T) {
+	normal := algebra.NewVector(0, 1, 0)
+	rng := rand.New(rand.NewSource(1))
+	sum := 0.0
+	n := 2000
+	for i := 0; i < n; i++ {
+		d := cosineDirection(normal, rng)
+		if math.Abs(d.Magnitude()-1) > 1e-9 || d.Get()[1] < 0 {
+			t.Fatalf("Expected a unit vector above the surface, got %v", d.Get())
+		}
+		sum += d.Get()[1]
+	}
+	// the average cosine of cosine weighted directions is 2/3
+	if average := sum / float64(n); math.Abs(average-2.0/3) > 0.02 {
+		t.Errorf("Expected an average cosine of %f, got %f", 2.0/3, average)
+	}
+}
+
+func TestPathTracer_LiEnvironment(t *testing.T) {
+	image := canvas.NewCanvas(16, 8)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 16; x++ {
+			image.Pixels[y][x] = &canvas.Color{1, 1, 1}
+		}
+	}
+	floor := primitives.NewPlane(nil)
+	w := &World{Objects: []primitives.Shape{floor}, Background: canvas.NewEnvironmentMap(image, 1)}
+
+	// rays missing the floor see the environment
+	up := algebra.NewRay(0, 1, 0, 0, 1, 0)
+	testColorEquals(t, NewPathTracer(5).Li(w, up, rand.New(rand.NewSource(1))), &canvas.Color{1, 1, 1})
+
+	// a floor under a white sky reflects its Diffuse part of the light
+	down := algebra.NewRay(0, 1, 0, 0, -1, 0)
+	rng := rand.New(rand.NewSource(1))
+	sum := 0.0
+	n := 2000
+	for i := 0; i < n; i++ {
+		sum += NewPathTracer(0).Li(w, down, rng).Red()
+	}
+	if average := sum / float64(n); math.Abs(average-0.9) > 0.05 {
+		t.Errorf("Expected the floor to reflect %f of the sky, got %f", 0.9, average)
+	}
+}
@@ -0,0 +1,453 @@
+package geometry
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry/primitives"
+	"math"
+	"math/rand"
+)
+
+//PhotonMap holds photons shot from the lights of a World before rendering and traced through its reflective and
+// transparent objects. The caustic map stores the photons that reached a surface through mirrors and glass only, so
+// light focused by a glass sphere shows up on the floor under it. The optional global map stores the photons that
+// bounced off diffuse surfaces, which adds indirect diffuse light. World.ShadeHit estimates the light brought by the
+// photons around each hit from their density
+type PhotonMap struct {
+	CausticPhotons int     // photons aimed at the reflective and transparent objects of the World
+	GlobalPhotons  int     // photons shot in every direction for the indirect diffuse light, 0 disables it
+	Neighbours     int     // number of photons gathered for each estimate
+	Radius         float64 // largest distance photons are gathered from
+	MaxBounces     int     // maximum number of surfaces a photon is traced to
+	Seed           int64   // seed of the random numbers used to shoot photons, the same seed gives the same map
+	caustics       *kdTree
+	global         *kdTree
+	targets        map[primitives.Shape]bool // objects of the World caustic photons were aimed at
+}
+
+//NewPhotonMap returns a new PhotonMap shooting the given number of caustic and global photons, gathering 50 photons
+// within a radius of 0.5 for each estimate
+func NewPhotonMap(causticPhotons, globalPhotons int) *PhotonMap {
+	return &PhotonMap{CausticPhotons: causticPhotons, GlobalPhotons: globalPhotons, Neighbours: 50, Radius: 0.5,
+		MaxBounces: 8}
+}
+
+//SetPhotonMap shoots the photons of the map from the lights of the World and uses them when shading hits, nil
+// disables photon mapping. The photons are shot with the objects where they are at time 0, so the map must be set
+// again after the scene changes. With caustic photons, the bounded transparent objects photons are aimed at no longer
+// let light through to shadow rays: the light they pass on is carried by the photons instead
+func (w *World) SetPhotonMap(m *PhotonMap) {
+	w.photons = nil
+	if m != nil {
+		m.build(w)
+	}
+	w.photons = m
+}
+
+//Photons returns the photons stored in the caustic and global maps
+func (m *PhotonMap) Photons() ([]Photon, []Photon) {
+	var caustics, global []Photon
+	if m.caustics != nil {
+		caustics = m.caustics.photons
+	}
+	if m.global != nil {
+		global = m.global.photons
+	}
+	return caustics, global
+}
+
+//boundingSphere is a sphere enclosing a part of the World, photons are aimed at it
+type boundingSphere struct {
+	center *algebra.Vector
+	radius float64
+}
+
+//build shoots the photons from every light and stores them in kd-trees
+func (m *PhotonMap) build(w *World) {
+	m.caustics, m.global, m.targets = nil, nil, nil
+	if len(w.Lights) == 0 {
+		return
+	}
+	rng := rand.New(rand.NewSource(m.Seed))
+	scene, hasScene := sceneBounds(w.Objects)
+
+	var caustics []Photon
+	var targets []boundingSphere
+	aimed := make(map[primitives.Shape]bool)
+	for _, s := range w.Objects {
+		if b, ok := worldBounds(s); ok && isSpecular(s) {
+			targets = append(targets, b)
+			aimed[s] = true
+		}
+	}
+	if m.CausticPhotons > 0 && len(targets) > 0 {
+		n := maxInt(m.CausticPhotons/len(w.Lights), 1)
+		for _, l := range w.Lights {
+			caustics = append(caustics, m.shoot(w, l, targets, scene, n, true, rng)...)
+		}
+	}
+
+	var global []Photon
+	if m.GlobalPhotons > 0 && hasScene {
+		n := maxInt(m.GlobalPhotons/len(w.Lights), 1)
+		for _, l := range w.Lights {
+			global = append(global, m.shoot(w, l, nil, scene, n, false, rng)...)
+		}
+	}
+
+	if len(caustics) > 0 {
+		m.caustics = newKDTree(caustics)
+		m.targets = aimed
+	}
+	if len(global) > 0 {
+		m.global = newKDTree(global)
+	}
+}
+
+//shoot shoots n photons from the light and returns those stored. Caustic photons are aimed at the union of the
+// targets: each photon is aimed at one of them picked at random and its power is divided by the density of its
+// direction summed over every target it could have been aimed at, so the light reaching targets that overlap is not
+// counted twice. Global photons are shot in every direction from point lights, over the whole scene from lights at
+// infinity and over the outward hemisphere of the surface of geometry lights. The power of the light is split
+// between the photons so that their density gives the light reaching a surface
+func (m *PhotonMap) shoot(w *World, l canvas.Light, targets []boundingSphere, scene boundingSphere, n int, caustic bool, rng *rand.Rand) []Photon {
+	aims := targets
+	if len(aims) == 0 {
+		aims = []boundingSphere{scene}
+	}
+	geometry, isGeometry := l.(*primitives.GeometryLight)
+	samples := make([][]*algebra.Vector, len(aims))
+	if !isGeometry {
+		for j, aim := range aims {
+			samples[j] = l.Samples(aim.center)
+		}
+	}
+	var stored []Photon
+	for i := 0; i < n; i++ {
+		j := rng.Intn(len(aims))
+		aim := aims[j]
+		var target *boundingSphere
+		if len(targets) > 0 {
+			target = &targets[j]
+		}
+		var ray *algebra.Ray
+		var power *canvas.Color
+		var firstHit func(dist float64) float64
+		if isGeometry {
+			// glowing surfaces give off photons from points spread over them, each standing for the light of its
+			// area going out in the direction of the photon
+			point, normal, light := geometry.Emit(rng.Float64(), rng.Float64(), rng.Float64())
+			var direction *algebra.Vector
+			if target != nil {
+				direction, _ = sampleCone(point, target, rng)
+			} else {
+				direction = cosineDirection(normal, rng)
+			}
+			cos, err := algebra.DotProduct(direction, normal)
+			if err != nil {
+				panic(err)
+			}
+			if cos <= 0 {
+				continue
+			}
+			density := cos / math.Pi
+			if target != nil {
+				density = coneDensity(point, direction, targets, j)
+			}
+			ray = newRay(add(point, direction.MultScalar(0.0001)), direction)
+			power = light.ScalarMult(cos / (float64(n) * density))
+			// the spread of the photons over the surface already gives the inverse square law
+			firstHit = l.GetFalloff().Attenuation
+		} else if len(samples[j]) == 0 {
+			continue
+		} else if sample := samples[j][rng.Intn(len(samples[j]))]; sample.Get()[3] == 0 {
+			// lights at infinity shoot parallel photons through a disc covering what they are aimed at
+			direction, err := sample.Negate().Normalize()
+			if err != nil {
+				panic(err)
+			}
+			u, v := algebra.OrthonormalBasis(direction)
+			r := aim.radius * math.Sqrt(rng.Float64())
+			phi := 2 * math.Pi * rng.Float64()
+			// start the photons outside of the whole scene
+			back := aim.radius + scene.radius + distance(aim.center, scene.center)
+			origin := add(aim.center, u.MultScalar(r*math.Cos(phi)), v.MultScalar(r*math.Sin(phi)),
+				direction.MultScalar(-back))
+			ray = newRay(origin, direction)
+			power = l.IntensityAt(origin).ScalarMult(1 / (float64(n) * discDensity(origin, direction, aims, j)))
+		} else {
+			direction, _ := sampleCone(sample, target, rng)
+			ray = newRay(sample, direction)
+			density := 1 / (4 * math.Pi)
+			if target != nil {
+				density = coneDensity(sample, direction, targets, j)
+			}
+			power = l.IntensityAt(add(sample, direction)).ScalarMult(1 / (float64(n) * density))
+			// photons from a point spread out with the square of the distance, which the falloff of the light
+			// replaces
+			falloff := l.GetFalloff()
+			firstHit = func(dist float64) float64 {
+				return falloff.Attenuation(dist) * dist * dist
+			}
+		}
+		stored = append(stored, m.trace(w, ray, power, firstHit, caustic, rng)...)
+	}
+	return stored
+}
+
+//trace follows a photon of the given power through the World and returns the photons it leaves on surfaces. When
+// firstHit is not nil, the power of the photon is multiplied at its first hit by firstHit of the distance travelled,
+// so that photons from lights with a position light surfaces the same way the light itself does. Caustic photons are
+// stored on the first diffuse surface they reach after mirrors and glass, global photons on every diffuse surface
+// after their first diffuse bounce
+func (m *PhotonMap) trace(w *World, ray *algebra.Ray, power *canvas.Color, firstHit func(dist float64) float64, caustic bool, rng *rand.Rand) []Photon {
+	var stored []Photon
+	throughput := &canvas.Color{1, 1, 1}
+	specular, diffuse := false, false
+	for bounce := 0; bounce < m.MaxBounces; bounce++ {
+		is := w.Intersect(ray)
+		hit := is.Hit()
+		if hit == nil {
+			break
+		}
+		comps := PrepareComputations(hit, ray, is)
+		if bounce == 0 && firstHit != nil {
+			power = power.ScalarMult(firstHit(hit.T))
+		}
+		material := comps.Object.GetMaterial()
+		albedo := material.Color
+		if material.Pattern != nil {
+			albedo = primitives.PatternAtObjectTime(comps.Object, material.Pattern, comps.Point, comps.Time)
+		}
+		if material.Diffuse > 0 && ((caustic && specular) || (!caustic && diffuse)) {
+			stored = append(stored, Photon{Position: comps.Point, Direction: comps.Eye.Negate(),
+				Power: canvas.Multiply(power, throughput)})
+		}
+
+		next, weight, isSpecular := scatter(comps, material, albedo, rng)
+		if next == nil || (caustic && !isSpecular) {
+			break
+		}
+		specular = specular || isSpecular
+		diffuse = diffuse || !isSpecular
+		throughput = canvas.Multiply(throughput, weight)
+		if bounce >= ROULETTEDEPTH {
+			survival := math.Min(math.Max(throughput[0], math.Max(throughput[1], throughput[2])), 0.95)
+			if rng.Float64() >= survival {
+				break
+			}
+			throughput = throughput.ScalarMult(1 / survival)
+		}
+		ray = next
+	}
+	return stored
+}
+
+//radiance returns the light the photons around the hit bring to the diffuse part of the material
+func (m *PhotonMap) radiance(comps *Comps, material *canvas.Material, albedo *canvas.Color) *canvas.Color {
+	sum := m.estimate(m.caustics, comps).Add(m.estimate(m.global, comps))
+	return canvas.Multiply(albedo, sum).ScalarMult(material.Diffuse)
+}
+
+//estimate returns the density of the power of the photons of the tree around the hit that landed on the side of
+// the surface facing the eye. Photons are weighted by a cone filter, closer photons count more, which keeps the edges
+// of caustics sharp
+func (m *PhotonMap) estimate(tree *kdTree, comps *Comps) *canvas.Color {
+	sum := &canvas.Color{0, 0, 0}
+	found := tree.nearest(comps.Point, m.Neighbours, m.Radius)
+	if len(found) == 0 {
+		return sum
+	}
+	r2 := 0.0
+	for _, n := range found {
+		r2 = math.Max(r2, n.dist2)
+	}
+	if r2 <= 0 {
+		return sum
+	}
+	r := math.Sqrt(r2)
+	for _, n := range found {
+		d, err := algebra.DotProduct(n.photon.Direction, comps.Normal)
+		if err != nil {
+			panic(err)
+		}
+		if d >= 0 {
+			continue
+		}
+		sum = sum.Add(n.photon.Power.ScalarMult(1 - math.Sqrt(n.dist2)/r))
+	}
+	// the cone filter spreads the power over a third of the disc of radius r
+	return sum.ScalarMult(3 / (math.Pi * r2))
+}
+
+//carries returns whether the caustic photons of the map carry the light passing through the shape, which they do for
+// the objects caustic photons were aimed at and their parts. Unbounded objects are never aimed at
+func (m *PhotonMap) carries(s primitives.Shape) bool {
+	if m == nil || m.caustics == nil {
+		return false
+	}
+	for ; s != nil; s = s.GetParent() {
+		if m.targets[s] {
+			return true
+		}
+	}
+	return false
+}
+
+//sampleCone returns a random direction from the point origin towards the target and the solid angle directions are
+// picked from. Directions are picked over the whole sphere without a target or from inside it
+func sampleCone(origin *algebra.Vector, target *boundingSphere, rng *rand.Rand) (*algebra.Vector, float64) {
+	axis, cosMax := cone(origin, target)
+	u, v := algebra.OrthonormalBasis(axis)
+	cos := 1 - rng.Float64()*(1-cosMax)
+	sin := math.Sqrt(math.Max(0, 1-cos*cos))
+	phi := 2 * math.Pi * rng.Float64()
+	direction := add(u.MultScalar(sin*math.Cos(phi)), v.MultScalar(sin*math.Sin(phi)), axis.MultScalar(cos))
+	return direction, 2 * math.Pi * (1 - cosMax)
+}
+
+//cone returns the unit axis of the cone of directions from the point origin towards the target and the cosine of its
+// half angle, which is -1 for the whole sphere of directions without a target or from inside it
+func cone(origin *algebra.Vector, target *boundingSphere) (*algebra.Vector, float64) {
+	cosMax := -1.0
+	axis := algebra.NewVector(0, 1, 0)
+	if target != nil {
+		toTarget, err := target.center.Subtract(origin)
+		if err != nil {
+			panic(err)
+		}
+		if dist := toTarget.Magnitude(); dist > target.radius {
+			cosMax = math.Sqrt(1 - (target.radius*target.radius)/(dist*dist))
+			axis = toTarget
+		}
+	}
+	axis, err := axis.Normalize()
+	if err != nil {
+		panic(err)
+	}
+	return axis, cosMax
+}
+
+//coneDensity returns the density, per unit solid angle, of the direction from origin picked by sampleCone towards
+// the target chosen, one of the targets picked at random. The cones of the other targets containing the direction
+// could have given it too
+func coneDensity(origin, direction *algebra.Vector, targets []boundingSphere, chosen int) float64 {
+	density := 0.0
+	for k := range targets {
+		axis, cosMax := cone(origin, &targets[k])
+		cos, err := algebra.DotProduct(direction, axis)
+		if err != nil {
+			panic(err)
+		}
+		if k == chosen || cos >= cosMax {
+			density += 1 / (2 * math.Pi * (1 - cosMax))
+		}
+	}
+	return density / float64(len(targets))
+}
+
+//discDensity returns the density, per unit area across the direction, of the parallel photon starting at origin
+// aimed through the disc covering the target chosen, one of the targets picked at random. The discs of the other
+// targets the photon passes through could have given it too
+func discDensity(origin, direction *algebra.Vector, targets []boundingSphere, chosen int) float64 {
+	density := 0.0
+	for k, target := range targets {
+		toCenter, err := target.center.Subtract(origin)
+		if err != nil {
+			panic(err)
+		}
+		along, err := algebra.DotProduct(toCenter, direction)
+		if err != nil {
+			panic(err)
+		}
+		if k == chosen || distance(add(origin, direction.MultScalar(along)), target.center) <= target.radius {
+			density += 1 / (math.Pi * target.radius * target.radius)
+		}
+	}
+	return density / float64(len(targets))
+}
+
+//isSpecular returns whether the shape, or one of the shapes of a group, reflects or refracts light
+func isSpecular(s primitives.Shape) bool {
+	if g, ok := s.(*primitives.Group); ok {
+		for _, child := range g.GetShapes() {
+			if isSpecular(child) {
+				return true
+			}
+		}
+		return false
+	}
+	m := s.GetMaterial()
+	return m != nil && (m.Reflective > 0 || m.Transparency > 0)
+}
+
+//worldBounds returns the sphere bounding the shape at time 0, false when the shape is unbounded
+func worldBounds(s primitives.Shape) (boundingSphere, bool) {
+	min, max := s.GetBounds()
+	if min == nil || max == nil {
+		return boundingSphere{}, false
+	}
+	// checked before transforming the bounds, infinite bounds would turn into NaN
+	for i := 0; i < 3; i++ {
+		if math.IsInf(min.Get()[i], 0) || math.IsInf(max.Get()[i], 0) {
+			return boundingSphere{}, false
+		}
+	}
+	b := primitives.GetBoundsTransform(min, max, primitives.TransformAt(s, 0))
+	min, max = b.Get()
+	center := algebra.NewPoint((min.Get()[0]+max.Get()[0])/2, (min.Get()[1]+max.Get()[1])/2,
+		(min.Get()[2]+max.Get()[2])/2)
+	return boundingSphere{center: center, radius: distance(min, max) / 2}, true
+}
+
+//sceneBounds returns the sphere bounding every bounded object, false when there is none
+func sceneBounds(objects []primitives.Shape) (boundingSphere, bool) {
+	low := []float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	high := []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	found := false
+	for _, s := range objects {
+		b, ok := worldBounds(s)
+		if !ok {
+			continue
+		}
+		found = true
+		for i := 0; i < 3; i++ {
+			low[i] = math.Min(low[i], b.center.Get()[i]-b.radius)
+			high[i] = math.Max(high[i], b.center.Get()[i]+b.radius)
+		}
+	}
+	if !found {
+		return boundingSphere{center: algebra.NewPoint(0, 0, 0)}, false
+	}
+	min, max := algebra.NewPoint(low...), algebra.NewPoint(high...)
+	center := algebra.NewPoint((low[0]+high[0])/2, (low[1]+high[1])/2, (low[2]+high[2])/2)
+	return boundingSphere{center: center, radius: distance(min, max) / 2}, true
+}
+
+//distance returns the distance between the points a and b
+func distance(a, b *algebra.Vector) float64 {
+	d, err := b.Subtract(a)
+	if err != nil {
+		panic(err)
+	}
+	return d.Magnitude()
+}
+
+//add returns the sum of the point or vector v and the vectors
+func add(v *algebra.Vector, vectors ...*algebra.Vector) *algebra.Vector {
+	for _, u := range vectors {
+		var err error
+		v, err = v.Add(u)
+		if err != nil {
+			panic(err)
+		}
+	}
+	return v
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,199 @@
+package geometry
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/geometry/primitives"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+//glassBallScene returns a glass sphere floating above a floor, lit from above
+func glassBallScene() *World {
+	floor := primitives.NewPlane(nil)
+	glass := primitives.NewGlassSphere(algebra.TranslationMatrix(0, 2, 0), 1.5)
+	w := &World{Objects: []primitives.Shape{floor, glass}}
+	w.Lights = []canvas.Light{canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(0, 10, 0))}
+	return w
+}
+
+func TestWorld_SetPhotonMap(t *testing.T) {
+	w := glassBallScene()
+	under := algebra.NewRay(0.2, 0.5, 0, 0, -1, 0)
+	penumbra := algebra.NewRay(1, 0.5, 0, 0, -1, 0)
+	// without photons the glass lets the light through to the floor evenly
+	through := w.ColorAt(penumbra, 3)
+
+	m := NewPhotonMap(2000, 0)
+	w.SetPhotonMap(m)
+	caustics, global := m.Photons()
+	if len(caustics) == 0 || len(global) != 0 {
+		t.Fatalf("Expected caustic photons only, got %d caustic and %d global photons", len(caustics), len(global))
+	}
+	for _, p := range caustics {
+		onBall := math.Abs(distance(p.Position, algebra.NewPoint(0, 2, 0))-1) < 0.0001
+		if math.Abs(p.Position.Get()[1]) > 0.0001 && !onBall {
+			t.Fatalf("Expected the photons to land on the floor or the ball, got one at %v", p.Position.Get())
+		}
+	}
+
+	// the ball focuses the light under it and leaves a darker ring around
+	open := w.ColorAt(algebra.NewRay(3, 0.5, 0, 0, -1, 0), 3)
+	if focused := w.ColorAt(under, 3); focused.Red() <= open.Red() {
+		t.Errorf("Expected the caustic %v to be brighter than the open floor %v", focused, open)
+	}
+	if ring := w.ColorAt(penumbra, 3); ring.Red() >= through.Red() {
+		t.Errorf("Expected the ring around the caustic %v to be darker than %v", ring, through)
+	}
+
+	// the same seed shoots the same photons
+	again := NewPhotonMap(2000, 0)
+	w.SetPhotonMap(again)
+	if c, _ := again.Photons(); len(c) != len(caustics) || *c[0].Power != *caustics[0].Power {
+		t.Errorf("Expected the same photons from the same seed")
+	}
+
+	w.SetPhotonMap(nil)
+	testColorEquals(t, w.ColorAt(penumbra, 3), through)
+}
+
+func TestWorld_SetPhotonMapUnboundedGlass(t *testing.T) {
+	w := glassBallScene()
+	pane := primitives.NewPlane(algebra.TranslationMatrix(0, 8, 0))
+	m := canvas.NewDefaultMaterial()
+	m.Transparency = 0.5
+	m.Color = &canvas.Color{1, 0.5, 0.5}
+	pane.SetMaterial(m)
+	w.Objects = append(w.Objects, pane)
+	w.SetPhotonMap(NewPhotonMap(200, 0))
+
+	// photons are never aimed at the plane, so it keeps letting its tinted light through to shadow rays
+	open := algebra.NewPoint(3, 0.0001, 0)
+	testColorEquals(t, w.LightVisibility(open, w.Lights[0]), &canvas.Color{0.5, 0.25, 0.25})
+	// while the light through the ball is carried by the photons
+	under := algebra.NewPoint(0, 0.0001, 0)
+	testColorEquals(t, w.LightVisibility(under, w.Lights[0]), &canvas.Color{0, 0, 0})
+}
+
+func TestWorld_SetPhotonMapGlobal(t *testing.T) {
+	w := shadedRoom()
+	r := algebra.NewRay(0, 0.25, -1, 0, -0.25, 1)
+	shadowed := w.ColorAt(r, 3)
+
+	m := NewPhotonMap(0, 1000)
+	w.SetPhotonMap(m)
+	caustics, global := m.Photons()
+	if len(caustics) != 0 || len(global) == 0 {
+		t.Fatalf("Expected global photons only, got %d caustic and %d global photons", len(caustics), len(global))
+	}
+	// the light bounced off the ceiling reaches the floor in the shadow of the slab
+	if lit := w.ColorAt(r, 3); lit.Red() <= shadowed.Red() {
+		t.Errorf("Expected the indirect light to brighten %v, got %v", shadowed, lit)
+	}
+}
+
+func TestSampleCone(t *testing.T) {
+	target := &boundingSphere{center: algebra.NewPoint(0, 0, 10), radius: 1}
+	origin := algebra.NewPoint(0, 0, 0)
+	cosMax := math.Sqrt(1 - 1.0/100)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		d, solidAngle := sampleCone(origin, target, rng)
+		assertEquals(t, solidAngle, 2*math.Pi*(1-cosMax))
+		if math.Abs(d.Magnitude()-1) > 1e-9 || d.Get()[2] < cosMax-1e-9 {
+			t.Fatalf("Expected a unit vector towards the target, got %v", d.Get())
+		}
+	}
+	if _, solidAngle := sampleCone(origin, nil, rng); solidAngle != 4*math.Pi {
+		t.Errorf("Expected directions over the whole sphere, got a solid angle of %f", solidAngle)
+	}
+}
+
+func TestConeDensity(t *testing.T) {
+	origin := algebra.NewPoint(0, 0, 0)
+	target := boundingSphere{center: algebra.NewPoint(0, 0, 10), radius: 1}
+	solidAngle := 2 * math.Pi * (1 - math.Sqrt(1-1.0/100))
+	towards := algebra.NewVector(0, 0, 1)
+
+	// the same target twice is shot at as much as once
+	assertEquals(t, coneDensity(origin, towards, []boundingSphere{target}, 0), 1/solidAngle)
+	assertEquals(t, coneDensity(origin, towards, []boundingSphere{target, target}, 1), 1/solidAngle)
+	// a target apart gets half of the photons, each carrying twice the light
+	apart := boundingSphere{center: algebra.NewPoint(10, 0, 0), radius: 1}
+	assertEquals(t, coneDensity(origin, towards, []boundingSphere{target, apart}, 0), 0.5/solidAngle)
+}
+
+func TestDiscDensity(t *testing.T) {
+	origin := algebra.NewPoint(0, 20, 0)
+	down := algebra.NewVector(0, -1, 0)
+	target := boundingSphere{center: algebra.NewPoint(0, 0, 0), radius: 2}
+	inside := boundingSphere{center: algebra.NewPoint(0.5, 1, 0), radius: 1}
+	apart := boundingSphere{center: algebra.NewPoint(10, 0, 0), radius: 1}
+
+	assertEquals(t, discDensity(origin, down, []boundingSphere{target}, 0), 1/(4*math.Pi))
+	// the photon passes through the discs of both overlapping targets
+	expected := (1/(4*math.Pi) + 1/math.Pi) / 2
+	if d := discDensity(origin, down, []boundingSphere{target, inside}, 0); math.Abs(d-expected) > 1e-9 {
+		t.Errorf("Expected %f, Got: %f", expected, d)
+	}
+	assertEquals(t, discDensity(origin, down, []boundingSphere{target, apart}, 0), 1/(8*math.Pi))
+}
+
+func TestWorld_SetPhotonMapGeometryLight(t *testing.T) {
+	glowing := canvas.NewDefaultMaterial()
+	glowing.Emission = &canvas.Color{1, 1, 1}
+	glowing.EmissionStrength = 1
+	lamp := primitives.NewSphere(algebra.Multiply(algebra.TranslationMatrix(0, 4, 0),
+		algebra.ScalingMatrix(0.5, 0.5, 0.5)))
+	lamp.SetMaterial(glowing)
+	// a thin cube, caustic photons are only aimed at bounded objects
+	mirror := primitives.NewCube(algebra.ScalingMatrix(2, 0.01, 2))
+	m := canvas.NewDefaultMaterial()
+	m.Diffuse = 0
+	m.Specular = 0
+	m.Reflective = 1
+	mirror.SetMaterial(m)
+	ceiling := primitives.NewPlane(algebra.TranslationMatrix(0, 6, 0))
+	white := canvas.NewDefaultMaterial()
+	white.Diffuse = 1
+	white.Specular = 0
+	white.Ambient = 0
+	ceiling.SetMaterial(white)
+	light, err := primitives.NewGeometryLight(lamp, 16, false)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	w := &World{Objects: []primitives.Shape{lamp, mirror, ceiling}, Lights: []canvas.Light{light}}
+	photons := NewPhotonMap(12000, 0)
+	photons.Neighbours = 500
+	photons.Radius = 1.5
+	w.SetPhotonMap(photons)
+
+	// the ceiling sees the lamp mirrored under the mirror, lighting it like a lamp there would
+	image := primitives.NewSphere(algebra.Multiply(algebra.TranslationMatrix(0, -3.98, 0),
+		algebra.ScalingMatrix(0.5, 0.5, 0.5)))
+	image.SetMaterial(glowing)
+	mirrored, err := primitives.NewGeometryLight(image, 64, false)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	p := algebra.NewPoint(2.5, 6, 0)
+	normal := algebra.NewVector(0, -1, 0)
+	samples, weights := canvas.LightSamples(mirrored, p)
+	expected := canvas.LightingSamples(white, nil, mirrored, samples, weights, p, normal, normal,
+		&canvas.Color{1, 1, 1})
+
+	r := algebra.NewRay(2.5, 0, 0, 0, 1, 0)
+	i := primitives.NewIntersection(ceiling, 6)
+	xs := primitives.NewIntersections()
+	xs.GetHits().Push(i)
+	comps := PrepareComputations(i, r, xs)
+	got := photons.radiance(comps, white, white.Color)
+	if math.Abs(got.Red()-expected.Red()) > 0.1*expected.Red() {
+		t.Errorf("Expected the caustic photons to light the ceiling like the mirrored lamp with %f, got %f",
+			expected.Red(), got.Red())
+	}
+}
@@ -11,6 +11,7 @@ type Cone struct {
 	parent    Shape
 	closed    bool //determines if the cone is hollow or has caps on the ends
 	transform *algebra.Matrix
+	motion    *Motion
 	material  *canvas.Material
 	maximum   float64 //maximum y-value by default without transformations
 	minimum   float64 //minimum y-value by default without transformations
@@ -48,6 +49,17 @@ func (cone *Cone) GetTransform() *algebra.Matrix {
 	return cone.transform
 }
 
+//SetMotion Setter for the Cone's motion over the shutter interval, nil makes it static
+func (cone *Cone) SetMotion(m *Motion) {
+	cone.motion = m
+	RefreshBounds(cone)
+}
+
+//GetMotion Getter for the Cone's motion, nil when it is static
+func (cone *Cone) GetMotion() *Motion {
+	return cone.motion
+}
+
 //GetMaterial Getter for Cylinder Shape material
 func (cone *Cone) GetMaterial() *canvas.Material {
 	return cone.material
@@ -10,6 +10,7 @@ import (
 type Cube struct {
 	parent    Shape
 	transform *algebra.Matrix
+	motion    *Motion
 	material  *canvas.Material
 }
 
@@ -29,6 +30,17 @@ func (c *Cube) GetTransform() *algebra.Matrix {
 	return c.transform
 }
 
+//SetMotion Setter for the Cube's motion over the shutter interval, nil makes it static
+func (c *Cube) SetMotion(m *Motion) {
+	c.motion = m
+	RefreshBounds(c)
+}
+
+//GetMotion Getter for the Cube's motion, nil when it is static
+func (c *Cube) GetMotion() *Motion {
+	return c.motion
+}
+
 //SetTransform Setter for Cube transform, Shape interface method
 func (c *Cube) SetTransform(m *algebra.Matrix) {
 	if len(m.Get()) != 4 || len(m.Get()[0]) != 4 {
@@ -138,3 +150,50 @@ func min(values ...float64) float64 {
 	}
 	return minVal
 }
+
+//SurfaceArea returns the area of the six faces of the cube
+func (c *Cube) SurfaceArea() float64 {
+	return 24
+}
+
+//SurfacePoint returns a point of one of the six faces of the cube and the normal of the face, u picks the face and
+// the position along its first axis, v the position along its second axis
+func (c *Cube) SurfacePoint(u, v float64) (*algebra.Vector, *algebra.Vector) {
+	face := int(math.Min(5, math.Floor(u*6)))
+	a, b := 2*(u*6-float64(face))-1, 2*v-1
+	side := 1.0
+	if face%2 == 1 {
+		side = -1
+	}
+	normal := []float64{0, 0, 0}
+	normal[face/2] = side
+	switch face / 2 {
+	case 0:
+		return algebra.NewPoint(side, a, b), algebra.NewVector(normal...)
+	case 1:
+		return algebra.NewPoint(a, side, b), algebra.NewVector(normal...)
+	}
+	return algebra.NewPoint(a, b, side), algebra.NewVector(normal...)
+}
+
+//SurfacePointFacing returns a point of the faces of the cube seen from p, u picks the face and the position along its
+// first axis, v the position along its second axis. It also returns the normal of the face and the area of the faces
+// seen from p, none when p is inside the cube
+func (c *Cube) SurfacePointFacing(p *algebra.Vector, u, v float64) (*algebra.Vector, *algebra.Vector, float64) {
+	var faces []int // numbered like in SurfacePoint, 2 * axis plus 1 on the negative side
+	for axis := 0; axis < 3; axis++ {
+		if p.Get()[axis] > 1 {
+			faces = append(faces, 2*axis)
+		} else if p.Get()[axis] < -1 {
+			faces = append(faces, 2*axis+1)
+		}
+	}
+	area := 4 * float64(len(faces))
+	if len(faces) == 0 {
+		faces = []int{0, 1, 2, 3, 4, 5}
+	}
+	n := float64(len(faces))
+	i := int(math.Min(n-1, math.Floor(u*n)))
+	point, normal := c.SurfacePoint((float64(faces[i])+u*n-float64(i))/6, v)
+	return point, normal, area
+}
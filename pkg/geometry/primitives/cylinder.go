@@ -11,6 +11,7 @@ type Cylinder struct {
 	parent    Shape
 	closed    bool //determines if the cylinder is hollow or has caps on the ends
 	transform *algebra.Matrix
+	motion    *Motion
 	material  *canvas.Material
 	maximum   float64 //maximum y-value by default without transformations
 	minimum   float64 //minimum y-value by default without transformations
@@ -48,6 +49,17 @@ func (cyl *Cylinder) GetTransform() *algebra.Matrix {
 	return cyl.transform
 }
 
+//SetMotion Setter for the Cylinder's motion over the shutter interval, nil makes it static
+func (cyl *Cylinder) SetMotion(m *Motion) {
+	cyl.motion = m
+	RefreshBounds(cyl)
+}
+
+//GetMotion Getter for the Cylinder's motion, nil when it is static
+func (cyl *Cylinder) GetMotion() *Motion {
+	return cyl.motion
+}
+
 //GetMaterial Getter for Cylinder Shape material
 func (cyl *Cylinder) GetMaterial() *canvas.Material {
 	return cyl.material
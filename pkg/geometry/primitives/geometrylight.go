@@ -0,0 +1,296 @@
+package primitives
+
+import (
+	"fmt"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+//NotEmissive is returned when a GeometryLight is made from a Shape without any emissive surface to sample
+type NotEmissive struct {
+	Shape Shape
+}
+
+func (e NotEmissive) Error() string {
+	return fmt.Sprintf("Shape %T has no emissive surface that can be sampled", e.Shape)
+}
+
+//SurfaceSampler is implemented by shapes whose surface can be sampled, so that they can give off light
+type SurfaceSampler interface {
+	Shape
+	//SurfaceArea returns the area of the surface in object space
+	SurfaceArea() float64
+	//SurfacePoint returns the point of the surface at the coordinates u, v in [0, 1) and the unit normal of the
+	// surface there, both in object space. Evenly spread coordinates give points evenly spread over the surface
+	SurfacePoint(u, v float64) (*algebra.Vector, *algebra.Vector)
+	//SurfacePointFacing returns the point at the coordinates u, v in [0, 1) of the part of the surface facing the
+	// point p, the normal of the surface there and the area of that part, all in object space. Evenly spread
+	// coordinates give points evenly spread over the part
+	SurfacePointFacing(p *algebra.Vector, u, v float64) (*algebra.Vector, *algebra.Vector, float64)
+}
+
+//AREAGRID is the number of points along each side of the grid of points of the surface of an emitter of a
+// GeometryLight its area in world space is averaged over
+const AREAGRID = 12
+
+//emitter is a part of the surface of a GeometryLight
+type emitter struct {
+	shape     SurfaceSampler
+	transform *algebra.Matrix // object to world space transform of the shape
+	inverse   *algebra.Matrix // world to object space transform of the shape
+	normals   *algebra.Matrix // object to world space transform of the normals, the transpose of inverse
+	det       float64         // ratio of volumes in world space to volumes in object space
+	area      float64         // area of the surface in world space
+	emitted   *canvas.Color   // light given off by each unit of area in each direction
+	twoSided  bool            // whether both sides of the surface give off light, as for triangles
+}
+
+//areaScale returns the ratio of areas in world space to areas in object space of the surface of the emitter around
+// a point where its unit normal in object space is normal
+func (e emitter) areaScale(normal *algebra.Vector) float64 {
+	n := e.normals.MultiplyByVec(normal)
+	n.Get()[3] = 0
+	return e.det * n.Magnitude()
+}
+
+//surfaceArea returns the area of the surface of the emitter in world space, its area in object space scaled by the
+// ratio of areas averaged over a grid of points of the surface. It is exact for the faces of cubes and triangles
+func (e emitter) surfaceArea() float64 {
+	sum := 0.0
+	for i := 0; i < AREAGRID; i++ {
+		for j := 0; j < AREAGRID; j++ {
+			_, normal := e.shape.SurfacePoint((float64(i)+0.5)/AREAGRID, (float64(j)+0.5)/AREAGRID)
+			sum += e.areaScale(normal)
+		}
+	}
+	return e.shape.SurfaceArea() * sum / (AREAGRID * AREAGRID)
+}
+
+//GeometryLight is a canvas.WeightedLight given off by the emissive surface of a Shape. The light is sampled at points
+// spread evenly over the part of the surface facing the lit point, each sample standing for the light of the area
+// around it in the Emitted color of its part of the surface, so a glowing object lights the scene like an area light
+// of the same shape and its light falls off with the square of the distance. Like other weighted lights it gives no
+// ambient term
+type GeometryLight struct {
+	Shape      Shape
+	NumSamples int
+	Jitter     bool           // jitter the samples over the surface instead of using a fixed pattern
+	Falloff    canvas.Falloff // applied on top of the inverse square law of the surface, NoFalloff unless set
+	intensity  *canvas.Color
+	emitters   []emitter
+	cdf        []float64 // cumulated areas of the emitters in world space, divided by the total area
+}
+
+//NewGeometryLight returns a light given off by the emissive parts of the shape, sampled at the given number of
+// points. Groups, such as the meshes of parsed OBJ files, are searched for emissive spheres, cubes and triangles.
+// Each sample gives the Emitted color of the material of its shape, the light given off by each unit of area in each
+// direction. Shapes are sampled where they are at time 0 and later changes to their
+// transforms are not seen by the light. A NotEmissive error is returned when the shape has no emissive surface
+func NewGeometryLight(shape Shape, samples int, jitter bool) (*GeometryLight, error) {
+	l := &GeometryLight{Shape: shape, NumSamples: samples, Jitter: jitter, intensity: &canvas.Color{0, 0, 0}}
+	l.addEmitters(shape)
+	total := 0.0
+	for _, e := range l.emitters {
+		total += e.area
+	}
+	if total <= 0 {
+		return nil, NotEmissive{Shape: shape}
+	}
+	sum := 0.0
+	for _, e := range l.emitters {
+		sum += e.area
+		l.cdf = append(l.cdf, sum/total)
+		l.intensity = l.intensity.Add(e.emitted.ScalarMult(e.area / total))
+	}
+	return l, nil
+}
+
+//addEmitters adds the emissive surfaces of s and of its children
+func (l *GeometryLight) addEmitters(s Shape) {
+	if g, ok := s.(*Group); ok {
+		for _, child := range g.GetShapes() {
+			l.addEmitters(child)
+		}
+		return
+	}
+	sampler, ok := s.(SurfaceSampler)
+	if !ok || s.GetMaterial() == nil || !s.GetMaterial().IsEmissive() {
+		return
+	}
+	transform := worldTransform(s)
+	det, err := algebra.Determinant([][]float64{
+		transform.Get()[0][:3], transform.Get()[1][:3], transform.Get()[2][:3]})
+	if err != nil {
+		panic(err)
+	}
+	inverse := transform.Inverse()
+	e := emitter{shape: sampler, transform: transform, inverse: inverse, normals: inverse.Transpose(),
+		det: math.Abs(det), emitted: s.GetMaterial().Emitted()}
+	e.area = e.surfaceArea()
+	switch s.(type) {
+	case *Triangle, *SmoothTriangle:
+		e.twoSided = true
+	}
+	l.emitters = append(l.emitters, e)
+}
+
+//Contains returns whether the shape s is one of the emissive surfaces of the light
+func (l *GeometryLight) Contains(s Shape) bool {
+	for _, e := range l.emitters {
+		if Shape(e.shape) == s {
+			return true
+		}
+	}
+	return false
+}
+
+//GetIntensity returns the Emitted color of the emissive surfaces averaged over their areas
+func (l *GeometryLight) GetIntensity() *canvas.Color {
+	return l.intensity
+}
+
+//IntensityAt returns white, the weights of the samples carry the light of each of them reaching p
+func (l *GeometryLight) IntensityAt(p *algebra.Vector) *canvas.Color {
+	return &canvas.Color{1, 1, 1}
+}
+
+//GetFalloff returns how the light decreases with the distance to it
+func (l *GeometryLight) GetFalloff() canvas.Falloff {
+	return l.Falloff
+}
+
+//Samples returns points spread over the part of the emissive surface facing p in proportion to the area of its parts
+func (l *GeometryLight) Samples(p *algebra.Vector) []*algebra.Vector {
+	samples, _ := l.WeightedSamples(p)
+	return samples
+}
+
+//WeightedSamples returns points spread over the part of the emissive surface facing p in proportion to the area of
+// its parts, and the weight of each sample: the Emitted color of its part of the surface times the area it stands for
+// times the cosine of the angle between the normal of the surface and the direction to p, divided by the squared
+// distance to p. For a surface of a single color the weights average to that color times the solid angle the surface
+// covers seen from p, the light reaching p
+func (l *GeometryLight) WeightedSamples(p *algebra.Vector) ([]*algebra.Vector, []*canvas.Color) {
+	n := l.NumSamples
+	if n < 1 {
+		n = 1
+	}
+	samples := make([]*algebra.Vector, 0, n)
+	weights := make([]*canvas.Color, 0, n)
+	for k := 0; k < n; k++ {
+		du, dv := 0.5, 0.0
+		if l.Jitter {
+			du, dv = canvas.Jitter(p, k, 0)
+		}
+		// stratified along u to pick the emitter and the first coordinate, the van der Corput sequence along v
+		u := (float64(k) + du) / float64(n)
+		v := math.Mod(float64(bits.Reverse32(uint32(k)))/(1<<32)+dv, 1)
+		i, localU, probability := l.pick(u)
+		e := l.emitters[i]
+		point, normal, area := e.shape.SurfacePointFacing(e.inverse.MultiplyByVec(p), localU, v)
+		sample := e.transform.MultiplyByVec(point)
+		weight := &canvas.Color{0, 0, 0}
+		if area > 0 && probability > 0 {
+			normal = e.normals.MultiplyByVec(normal)
+			normal.Get()[3] = 0
+			toP, err := p.Subtract(sample)
+			if err != nil {
+				panic(err)
+			}
+			cos, err := algebra.DotProduct(normal, toP)
+			if err != nil {
+				panic(err)
+			}
+			dist := toP.Magnitude()
+			if cos > 0 && dist > 0 {
+				// the facing area of the emitter in world space, det times the length of the transformed normal
+				// per unit of area, divided by the probability of picking it, the cosine of the angle with its
+				// normal and the inverse square law
+				factor := area * e.det / probability * cos / (dist * dist * dist)
+				weight = e.emitted.ScalarMult(factor)
+			}
+		}
+		samples = append(samples, sample)
+		weights = append(weights, weight)
+	}
+	return samples, weights
+}
+
+//Emit returns a point of the emissive surface at the coordinates u, v in [0, 1), spread over the surface in
+// proportion to the area of its parts, the unit normal of the side of the surface giving off light there and the
+// Emitted color there divided by the density of the point per unit of area, all in world space. Spheres and cubes
+// give off light outwards and triangles on both sides, side in [0, 1) picking one of them
+func (l *GeometryLight) Emit(u, v, side float64) (*algebra.Vector, *algebra.Vector, *canvas.Color) {
+	i, localU, probability := l.pick(u)
+	e := l.emitters[i]
+	point, normal := e.shape.SurfacePoint(localU, v)
+	light := &canvas.Color{0, 0, 0}
+	if probability > 0 {
+		light = e.emitted.ScalarMult(e.shape.SurfaceArea() * e.areaScale(normal) / probability)
+	}
+	normal = e.normals.MultiplyByVec(normal)
+	normal.Get()[3] = 0
+	normal, err := normal.Normalize()
+	if err != nil {
+		panic(err)
+	}
+	if e.twoSided {
+		light = light.ScalarMult(2)
+		if side < 0.5 {
+			normal = normal.Negate()
+		}
+	}
+	return e.transform.MultiplyByVec(point), normal, light
+}
+
+//pick returns the index of the emitter picked by u in [0, 1), in proportion to the areas of the emitters, u rescaled
+// to [0, 1) within it and the probability of picking it
+func (l *GeometryLight) pick(u float64) (int, float64, float64) {
+	i := sort.SearchFloat64s(l.cdf, u)
+	if i >= len(l.cdf) {
+		i = len(l.cdf) - 1
+	}
+	low := 0.0
+	if i > 0 {
+		low = l.cdf[i-1]
+	}
+	localU := 0.0
+	if l.cdf[i] > low {
+		localU = math.Min((u-low)/(l.cdf[i]-low), 1)
+	}
+	return i, localU, l.cdf[i] - low
+}
+
+//DirectionFrom returns the unit vector pointing from p to the sample of the light and the distance between them
+func (l *GeometryLight) DirectionFrom(p, sample *algebra.Vector) (*algebra.Vector, float64) {
+	v, err := sample.Subtract(p)
+	if err != nil {
+		panic(err)
+	}
+	dist := v.Magnitude()
+	v, err = v.Normalize()
+	if err != nil {
+		panic(err)
+	}
+	return v, dist
+}
+
+//ShadowRay returns the ray cast from p towards the sample of the light. It stops just short of the sample so the
+// emissive surface does not shadow itself
+func (l *GeometryLight) ShadowRay(p, sample *algebra.Vector) (*algebra.Ray, float64) {
+	direction, dist := l.DirectionFrom(p, sample)
+	origin := []float64{p.Get()[0], p.Get()[1], p.Get()[2]}
+	return algebra.NewRay(append(origin, direction.Get()[:3]...)...), dist - 0.0001
+}
+
+//worldTransform returns the transform from the object space of s to world space at time 0
+func worldTransform(s Shape) *algebra.Matrix {
+	m := TransformAt(s, 0)
+	if s.GetParent() != nil {
+		m = algebra.Multiply(worldTransform(s.GetParent()), m)
+	}
+	return m
+}
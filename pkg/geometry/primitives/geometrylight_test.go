@@ -0,0 +1,222 @@
+package primitives
+
+import (
+	"errors"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/canvas"
+	"math"
+	"testing"
+)
+
+func emissiveMaterial() *canvas.Material {
+	m := canvas.NewDefaultMaterial()
+	m.Emission = &canvas.Color{1, 0.5, 0.25}
+	m.EmissionStrength = 2
+	return m
+}
+
+func TestSurfacePoint(t *testing.T) {
+	sphere := NewSphere(nil)
+	cube := NewCube(nil)
+	for _, uv := range [][2]float64{{0, 0}, {0.3, 0.7}, {0.5, 0.5}, {0.99, 0.1}} {
+		point, normal := sphere.SurfacePoint(uv[0], uv[1])
+		p := point.Get()
+		if !equals(math.Sqrt(p[0]*p[0]+p[1]*p[1]+p[2]*p[2]), 1) {
+			t.Errorf("Expected %v to be on the unit sphere", p)
+		}
+		testVectorEquals(t, normal.Get(), []float64{p[0], p[1], p[2], 0})
+		point, normal = cube.SurfacePoint(uv[0], uv[1])
+		p = point.Get()
+		if !equals(math.Max(math.Abs(p[0]), math.Max(math.Abs(p[1]), math.Abs(p[2]))), 1) {
+			t.Errorf("Expected %v to be on the cube", p)
+		}
+		if d, _ := algebra.DotProduct(normal, algebra.NewVector(p[0], p[1], p[2])); !equals(d, 1) {
+			t.Errorf("Expected %v to be the normal of the face of %v", normal.Get(), p)
+		}
+	}
+
+	triangle := NewTriangle(algebra.NewPoint(0, 0, 0), algebra.NewPoint(2, 0, 0), algebra.NewPoint(0, 2, 0))
+	assertEquals(t, triangle.SurfaceArea(), 2)
+	point, normal := triangle.SurfacePoint(0, 0)
+	testVectorEquals(t, point.Get(), []float64{0, 0, 0, 1})
+	testVectorEquals(t, normal.Get(), []float64{0, 0, -1, 0})
+	point, _ = triangle.SurfacePoint(1, 0)
+	testVectorEquals(t, point.Get(), []float64{2, 0, 0, 1})
+	point, _ = triangle.SurfacePoint(1, 1)
+	testVectorEquals(t, point.Get(), []float64{0, 2, 0, 1})
+}
+
+func TestNewGeometryLight(t *testing.T) {
+	s := NewSphere(algebra.Multiply(algebra.TranslationMatrix(0, 5, 0), algebra.ScalingMatrix(2, 2, 2)))
+	s.SetMaterial(emissiveMaterial())
+	l, err := NewGeometryLight(s, 16, true)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	testColorEquals(t, l.GetIntensity(), &canvas.Color{2, 1, 0.5})
+	testColorEquals(t, l.IntensityAt(algebra.NewPoint(0, 0, 0)), &canvas.Color{1, 1, 1})
+	samples, weights := l.WeightedSamples(algebra.NewPoint(0, 0, 0))
+	if len(samples) != 16 || len(weights) != 16 {
+		t.Errorf("Expected 16 samples, got %d", len(samples))
+	}
+	total := 0.0
+	for k, sample := range samples {
+		d, err := sample.Subtract(algebra.NewPoint(0, 5, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equals(d.Magnitude(), 2) {
+			t.Errorf("Expected sample %v to be on the surface of the sphere", sample.Get())
+		}
+		// the cap seen from the origin ends where the tangents from the origin touch the sphere
+		if sample.Get()[1] > 4.2+1e-9 {
+			t.Errorf("Expected sample %v to be on the part of the sphere facing the origin", sample.Get())
+		}
+		total += weights[k].Red() / 2
+	}
+	// the weights average to the emission times the solid angle of the sphere seen from the origin
+	solidAngle := 2 * math.Pi * (1 - math.Sqrt(1-0.4*0.4))
+	if math.Abs(total/16-solidAngle) > 0.02*solidAngle {
+		t.Errorf("Expected the weights to average to %f, got %f", solidAngle, total/16)
+	}
+
+	// the shadow ray stops short of the surface of the light
+	p := algebra.NewPoint(0, 0, 0)
+	r, dist := l.ShadowRay(p, algebra.NewPoint(0, 3, 0))
+	testVectorEquals(t, r.Get()["direction"].Get(), []float64{0, 1, 0, 0})
+	if dist >= 3 || dist < 2.99 {
+		t.Errorf("Expected the shadow ray to stop just before the light, got %f", dist)
+	}
+}
+
+func TestGeometryLight_WeightedSamplesCube(t *testing.T) {
+	c := NewCube(nil)
+	c.SetMaterial(emissiveMaterial())
+	l, err := NewGeometryLight(c, 32, false)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	samples, weights := l.WeightedSamples(algebra.NewPoint(5, 5, 0))
+	for k, sample := range samples {
+		p := sample.Get()
+		if !equals(p[0], 1) && !equals(p[1], 1) {
+			t.Errorf("Expected sample %v to be on one of the two faces facing the point", p)
+		}
+		if weights[k].Red() <= 0 {
+			t.Errorf("Expected sample %v of a facing side to give light, got a weight of %v", p, weights[k])
+		}
+	}
+	// a point inside the cube sees no part of its surface
+	_, weights = l.WeightedSamples(algebra.NewPoint(0, 0, 0))
+	for _, weight := range weights {
+		if *weight != (canvas.Color{0, 0, 0}) {
+			t.Errorf("Expected no light inside of the cube, got a weight of %v", weight)
+		}
+	}
+}
+
+func TestGeometryLight_WeightedSamplesPanel(t *testing.T) {
+	// a cube flattened into a 4 by 4 panel, seen from 4.99 below its lower face
+	panel := NewCube(algebra.ScalingMatrix(2, 0.01, 2))
+	m := canvas.NewDefaultMaterial()
+	m.Emission = &canvas.Color{1, 1, 1}
+	m.EmissionStrength = 1
+	panel.SetMaterial(m)
+	l, err := NewGeometryLight(panel, 64, false)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	total := 0.0
+	_, weights := l.WeightedSamples(algebra.NewPoint(0, -5, 0))
+	for _, weight := range weights {
+		total += weight.Red()
+	}
+	// the solid angle of a rectangle of half sides a and b seen from the distance d above its centre
+	a, b, d := 2.0, 2.0, 4.99
+	solidAngle := 4 * math.Asin(a*b/math.Sqrt((a*a+d*d)*(b*b+d*d)))
+	if math.Abs(total/64-solidAngle) > 0.02*solidAngle {
+		t.Errorf("Expected the weights to average to %f, got %f", solidAngle, total/64)
+	}
+	// the area of the panel is exact in world space, the thin sides count for little
+	if !equals(l.emitters[0].area, 2*16+4*4*0.02) {
+		t.Errorf("Expected an area of %f, got %f", 2*16+4*4*0.02, l.emitters[0].area)
+	}
+
+	// emitted points stand for the light of the whole surface, given off outwards
+	sum := 0.0
+	for i := 0; i < 12; i++ {
+		for j := 0; j < 4; j++ {
+			point, normal, light := l.Emit((float64(i)+0.5)/12, (float64(j)+0.5)/4, 0)
+			p := point.Get()
+			outwards, err := algebra.DotProduct(normal, algebra.NewVector(p[0], p[1], p[2]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equals(normal.Magnitude(), 1) || outwards <= 0 {
+				t.Errorf("Expected a unit normal pointing out of the panel at %v, got %v", p, normal.Get())
+			}
+			sum += light.Red()
+		}
+	}
+	if !equals(sum/48, 2*16+4*4*0.02) {
+		t.Errorf("Expected the emitted light to average to the area of the panel, got %f", sum/48)
+	}
+}
+
+func TestNewGeometryLightMesh(t *testing.T) {
+	g := NewGroup(algebra.TranslationMatrix(0, 10, 0))
+	small := NewTriangle(algebra.NewPoint(0, 0, 0), algebra.NewPoint(1, 0, 0), algebra.NewPoint(0, 0, 1))
+	large := NewTriangle(algebra.NewPoint(0, 0, 0), algebra.NewPoint(-3, 0, 0), algebra.NewPoint(0, 0, -3))
+	dark := NewTriangle(algebra.NewPoint(5, 0, 5), algebra.NewPoint(6, 0, 5), algebra.NewPoint(5, 0, 6))
+	g.AddChild(small)
+	g.AddChild(large)
+	g.AddChild(dark)
+	small.SetMaterial(emissiveMaterial())
+	blue := canvas.NewDefaultMaterial()
+	blue.Emission = &canvas.Color{0, 0, 1}
+	blue.EmissionStrength = 1
+	large.SetMaterial(blue)
+
+	l, err := NewGeometryLight(g, 100, false)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	onSmall, onLarge := 0, 0
+	samples, weights := l.WeightedSamples(algebra.NewPoint(0, 0, 0))
+	for k, sample := range samples {
+		p := sample.Get()
+		if !equals(p[1], 10) {
+			t.Errorf("Expected sample %v to be on the mesh", p)
+		}
+		switch {
+		case p[0] >= 0 && p[2] >= 0 && p[0]+p[2] <= 1:
+			onSmall++
+			// each sample gives the light of its own triangle
+			if w := weights[k]; w.Red() <= 0 || !equals(w.Green(), w.Red()/2) || !equals(w.Blue(), w.Red()/4) {
+				t.Errorf("Expected sample %v to give the light of the small triangle, got %v", p, w)
+			}
+		case p[0] <= 0 && p[2] <= 0 && p[0]+p[2] >= -3:
+			onLarge++
+			if w := weights[k]; w.Red() != 0 || w.Green() != 0 || w.Blue() <= 0 {
+				t.Errorf("Expected sample %v to give the blue light of the large triangle, got %v", p, w)
+			}
+		default:
+			t.Errorf("Expected sample %v to be on an emissive triangle", p)
+		}
+	}
+	// samples are spread in proportion to the area of the triangles
+	if onSmall != 10 || onLarge != 90 {
+		t.Errorf("Expected 10 and 90 samples on the small and large triangles, got %d and %d", onSmall, onLarge)
+	}
+
+	g.SetChildrenMaterial(canvas.NewDefaultMaterial())
+	var notEmissive NotEmissive
+	if _, err := NewGeometryLight(g, 4, false); !errors.As(err, &notEmissive) {
+		t.Errorf("Expected a shape without emission to give a NotEmissive error, got: %v", err)
+	}
+}
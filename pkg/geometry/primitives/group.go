@@ -18,6 +18,7 @@ type Group struct {
 	parent    Shape
 	shapes    []Shape
 	transform *algebra.Matrix
+	motion    *Motion
 	bounds    [2]*algebra.Vector
 }
 
@@ -48,6 +49,30 @@ func (g *Group) AddChild(s Shape) {
 	g.shapes = append(g.shapes, s)
 	min, max := g.getBounds()
 	g.bounds = [2]*algebra.Vector{min, max}
+	RefreshBounds(g)
+}
+
+//RefreshBounds recomputes the bounds cached by the groups holding the shape s, directly or through other groups,
+// after a change that moves s within its parent such as a new motion
+func RefreshBounds(s Shape) {
+	for p := s.GetParent(); p != nil; p = p.GetParent() {
+		if g, ok := p.(*Group); ok {
+			min, max := g.getBounds()
+			g.bounds = [2]*algebra.Vector{min, max}
+		}
+	}
+}
+
+//SetChildrenMaterial sets the material of every shape in the group and its sub groups, such as the triangles of a
+// parsed mesh
+func (g *Group) SetChildrenMaterial(m *canvas.Material) {
+	for _, s := range g.shapes {
+		if child, ok := s.(*Group); ok {
+			child.SetChildrenMaterial(m)
+		} else {
+			s.SetMaterial(m)
+		}
+	}
 }
 
 //Shape interface methods
@@ -67,6 +92,17 @@ func (g *Group) GetTransform() *algebra.Matrix {
 	return g.transform
 }
 
+//SetMotion Setter for the Group's motion over the shutter interval, nil makes it static
+func (g *Group) SetMotion(m *Motion) {
+	g.motion = m
+	RefreshBounds(g)
+}
+
+//GetMotion Getter for the Group's motion, nil when it is static
+func (g *Group) GetMotion() *Motion {
+	return g.motion
+}
+
 //SetTransform Setter for Shape transform
 func (g *Group) SetTransform(m *algebra.Matrix) {
 	if len(m.Get()) != 4 || len(m.Get()[0]) != 4 {
@@ -106,7 +142,7 @@ func (g *Group) getBounds() (*algebra.Vector, *algebra.Vector) {
 	for _, shape := range g.shapes {
 		tempMin, tempMax := shape.GetBounds()
 		if tempMin != nil {
-			b := GetBoundsTransform(tempMin, tempMax, shape.GetTransform())
+			b := MotionBounds(shape, tempMin, tempMax)
 			tempMin = b.minimum
 			tempMax = b.maximum
 			tempMinX := tempMin.Get()[0]
@@ -139,12 +175,13 @@ func (g *Group) LocalIntersect(r *algebra.Ray) ([]*Intersection, bool) {
 	if min == nil {
 		return xs, false
 	}
-	if GetBoundsTransform(min, max, g.GetTransform()).Intersect(r.Transform(g.GetTransform())) == false {
+	transform := TransformAt(g, r.Time())
+	if GetBoundsTransform(min, max, transform).Intersect(r.Transform(transform)) == false {
 		return xs, false
 	}
 
 	for _, s := range g.shapes {
-		m := s.GetTransform()
+		m := TransformAt(s, r.Time())
 		ri := r.Transform(m.Inverse())
 		shapeXs, shapeHit := s.LocalIntersect(ri)
 		hit = hit || shapeHit
@@ -47,7 +47,7 @@ func (i *Intersections) GetRef() *MinHeap {
 
 //Intersect Updates intersections of a Sphere with the given algebra.Ray
 func (intersections *Intersections) Intersect(s Shape, r *algebra.Ray) error {
-	m := s.GetTransform()
+	m := TransformAt(s, r.Time())
 	r2 := r.Transform(m.Inverse())
 
 	ts, intersected := s.LocalIntersect(r2)
@@ -0,0 +1,107 @@
+package primitives
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"math"
+	"sort"
+)
+
+//Keyframe is the transform of a moving Shape at a given instant of the shutter interval
+type Keyframe struct {
+	Time      float64
+	Transform *algebra.Matrix
+}
+
+//Motion describes how the transform of a Shape changes over the shutter interval. The transform is interpolated
+// linearly between keyframes and held constant before the first and after the last one. Matrices are interpolated
+// entry by entry, which is exact for translations and scalings, large rotations should be split into several
+// keyframes
+type Motion struct {
+	keyframes []Keyframe
+}
+
+//NewMotion returns a Motion going from the start transform at time 0 to the end transform at time 1
+func NewMotion(start, end *algebra.Matrix) *Motion {
+	return NewKeyframedMotion(Keyframe{Time: 0, Transform: start}, Keyframe{Time: 1, Transform: end})
+}
+
+//NewKeyframedMotion returns a Motion going through the given keyframes, they do not need to be ordered in time.
+// Keyframes whose transform is not 4x4 are ignored
+func NewKeyframedMotion(keyframes ...Keyframe) *Motion {
+	keys := make([]Keyframe, 0, len(keyframes))
+	for _, k := range keyframes {
+		if k.Transform == nil || len(k.Transform.Get()) != 4 || len(k.Transform.Get()[0]) != 4 {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].Time < keys[j].Time })
+	return &Motion{keyframes: keys}
+}
+
+//Keyframes returns the keyframes of the motion ordered in time
+func (m *Motion) Keyframes() []Keyframe {
+	return m.keyframes
+}
+
+//TransformAt returns the transform at the given time
+func (m *Motion) TransformAt(time float64) *algebra.Matrix {
+	keys := m.keyframes
+	if len(keys) == 0 {
+		return algebra.IdentityMatrix(4)
+	}
+	if time <= keys[0].Time {
+		return keys[0].Transform
+	}
+	last := keys[len(keys)-1]
+	if time >= last.Time {
+		return last.Transform
+	}
+	i := sort.Search(len(keys), func(i int) bool { return keys[i].Time > time })
+	a, b := keys[i-1], keys[i]
+	t := (time - a.Time) / (b.Time - a.Time)
+	return lerpMatrix(a.Transform, b.Transform, t)
+}
+
+//TransformAt returns the transform of the Shape at the given time, its Motion when it has one and
+// its static transform otherwise
+func TransformAt(s Shape, time float64) *algebra.Matrix {
+	if m := s.GetMotion(); m != nil {
+		return m.TransformAt(time)
+	}
+	return s.GetTransform()
+}
+
+//MotionBounds returns the bounding box, in the parent's space, of the bounds min and max of a shape
+// over its whole motion. Interpolated transforms move the corners along straight lines between keyframes so
+// the union of the boxes at every keyframe contains all of them
+func MotionBounds(s Shape, min, max *algebra.Vector) *Bounds {
+	m := s.GetMotion()
+	if m == nil || len(m.keyframes) == 0 {
+		return GetBoundsTransform(min, max, s.GetTransform())
+	}
+	low := []float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	high := []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, k := range m.keyframes {
+		b := GetBoundsTransform(min, max, k.Transform)
+		for i := 0; i < 3; i++ {
+			low[i] = math.Min(low[i], b.minimum.Get()[i])
+			high[i] = math.Max(high[i], b.maximum.Get()[i])
+		}
+	}
+	return &Bounds{minimum: algebra.NewPoint(low...), maximum: algebra.NewPoint(high...)}
+}
+
+func lerpMatrix(a, b *algebra.Matrix, t float64) *algebra.Matrix {
+	data := make([]float64, 0, 16)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			data = append(data, a.Get()[i][j]*(1-t)+b.Get()[i][j]*t)
+		}
+	}
+	m, err := algebra.NewMatrix(4, 4, data...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
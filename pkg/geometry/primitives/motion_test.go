@@ -0,0 +1,106 @@
+package primitives
+
+import (
+	"github.com/alexandreLamarre/Golang-Ray-Tracing-Renderer/pkg/algebra"
+	"testing"
+)
+
+func TestMotion_TransformAt(t *testing.T) {
+	m := NewMotion(algebra.TranslationMatrix(0, 0, 0), algebra.TranslationMatrix(4, 0, 0))
+	testMatrixEquals(t, m.TransformAt(0).Get(), algebra.TranslationMatrix(0, 0, 0).Get())
+	testMatrixEquals(t, m.TransformAt(0.25).Get(), algebra.TranslationMatrix(1, 0, 0).Get())
+	testMatrixEquals(t, m.TransformAt(1).Get(), algebra.TranslationMatrix(4, 0, 0).Get())
+	// held constant outside of the keyframes
+	testMatrixEquals(t, m.TransformAt(-1).Get(), algebra.TranslationMatrix(0, 0, 0).Get())
+	testMatrixEquals(t, m.TransformAt(2).Get(), algebra.TranslationMatrix(4, 0, 0).Get())
+
+	m = NewKeyframedMotion(
+		Keyframe{Time: 1, Transform: algebra.TranslationMatrix(0, 2, 0)},
+		Keyframe{Time: 0, Transform: algebra.TranslationMatrix(0, 0, 0)},
+		Keyframe{Time: 2, Transform: algebra.TranslationMatrix(2, 2, 0)},
+		Keyframe{Time: 3, Transform: nil})
+	if len(m.Keyframes()) != 3 {
+		t.Errorf("Expected invalid keyframes to be dropped, got %d keyframes", len(m.Keyframes()))
+	}
+	testMatrixEquals(t, m.TransformAt(0.5).Get(), algebra.TranslationMatrix(0, 1, 0).Get())
+	testMatrixEquals(t, m.TransformAt(1.5).Get(), algebra.TranslationMatrix(1, 2, 0).Get())
+}
+
+func TestTransformAt(t *testing.T) {
+	s := NewSphere(algebra.ScalingMatrix(2, 2, 2))
+	testMatrixEquals(t, TransformAt(s, 0.5).Get(), algebra.ScalingMatrix(2, 2, 2).Get())
+	s.SetMotion(NewMotion(algebra.TranslationMatrix(0, 0, 0), algebra.TranslationMatrix(2, 0, 0)))
+	testMatrixEquals(t, TransformAt(s, 0.5).Get(), algebra.TranslationMatrix(1, 0, 0).Get())
+	s.SetMotion(nil)
+	testMatrixEquals(t, TransformAt(s, 0.5).Get(), algebra.ScalingMatrix(2, 2, 2).Get())
+}
+
+func TestIntersections_IntersectMoving(t *testing.T) {
+	s := NewSphere(nil)
+	s.SetMotion(NewMotion(algebra.TranslationMatrix(0, 0, 0), algebra.TranslationMatrix(4, 0, 0)))
+	r := algebra.NewRay(4, 0, -5, 0, 0, 1)
+	is := NewIntersections()
+	if err := is.Intersect(s, r); err != nil {
+		t.Errorf("%s", err)
+	}
+	if is.Hit() != nil {
+		t.Errorf("Expected the ray to miss the sphere when the shutter opens")
+	}
+
+	r.SetTime(1)
+	is = NewIntersections()
+	if err := is.Intersect(s, r); err != nil {
+		t.Errorf("%s", err)
+	}
+	if h := is.Hit(); h == nil {
+		t.Errorf("Expected the ray to hit the sphere when the shutter closes")
+	} else {
+		assertEquals(t, h.T, 4)
+	}
+
+	n := NormalAtTime(s, algebra.NewPoint(4, 0, -1), nil, 1)
+	testVectorEquals(t, n.Get(), []float64{0, 0, -1, 0})
+}
+
+func TestGroup_BoundsMoving(t *testing.T) {
+	g := NewGroup(nil)
+	s := NewSphere(nil)
+	s.SetMotion(NewMotion(algebra.TranslationMatrix(0, 0, 0), algebra.TranslationMatrix(4, 0, 0)))
+	g.AddChild(s)
+	min, max := g.GetBounds()
+	testVectorEquals(t, min.Get(), []float64{-1, -1, -1, 1})
+	testVectorEquals(t, max.Get(), []float64{5, 1, 1, 1})
+
+	r := algebra.NewRay(4, 0, -5, 0, 0, 1)
+	r.SetTime(1)
+	if _, hit := g.LocalIntersect(r); !hit {
+		t.Errorf("Expected the ray to hit the moving child of the group")
+	}
+}
+
+func TestGroup_BoundsMovingAfterAddChild(t *testing.T) {
+	outer := NewGroup(nil)
+	inner := NewGroup(nil)
+	s := NewSphere(nil)
+	inner.AddChild(s)
+	outer.AddChild(inner)
+	// the motion is set once the sphere is in the groups, their bounds follow it
+	s.SetMotion(NewMotion(algebra.TranslationMatrix(0, 0, 0), algebra.TranslationMatrix(4, 0, 0)))
+	for _, g := range []*Group{inner, outer} {
+		min, max := g.GetBounds()
+		testVectorEquals(t, min.Get(), []float64{-1, -1, -1, 1})
+		testVectorEquals(t, max.Get(), []float64{5, 1, 1, 1})
+	}
+	r := algebra.NewRay(4, 0, -5, 0, 0, 1)
+	r.SetTime(1)
+	if _, hit := outer.LocalIntersect(r); !hit {
+		t.Errorf("Expected the ray to hit the child moved after it was added to the group")
+	}
+
+	// moving the inner group moves the bounds of the outer group, and the bounds of a child added later too
+	inner.SetMotion(NewMotion(algebra.TranslationMatrix(0, 0, 0), algebra.TranslationMatrix(0, 3, 0)))
+	inner.AddChild(NewSphere(algebra.TranslationMatrix(0, 0, -4)))
+	min, max := outer.GetBounds()
+	testVectorEquals(t, min.Get(), []float64{-1, -1, -5, 1})
+	testVectorEquals(t, max.Get(), []float64{5, 4, 1, 1})
+}
@@ -12,6 +12,7 @@ type Plane struct {
 	origin    *algebra.Vector
 	direction *algebra.Vector
 	transform *algebra.Matrix
+	motion    *Motion
 	material  *canvas.Material
 }
 
@@ -36,6 +37,17 @@ func (p *Plane) GetTransform() *algebra.Matrix {
 	return p.transform
 }
 
+//SetMotion Setter for the Plane's motion over the shutter interval, nil makes it static
+func (p *Plane) SetMotion(m *Motion) {
+	p.motion = m
+	RefreshBounds(p)
+}
+
+//GetMotion Getter for the Plane's motion, nil when it is static
+func (p *Plane) GetMotion() *Motion {
+	return p.motion
+}
+
 func (p *Plane) GetMaterial() *canvas.Material {
 	return p.material
 }
@@ -9,6 +9,8 @@ import (
 type Shape interface {
 	SetTransform(m *algebra.Matrix)
 	GetTransform() *algebra.Matrix
+	SetMotion(m *Motion)
+	GetMotion() *Motion
 	SetMaterial(m *canvas.Material)
 	GetMaterial() *canvas.Material
 	SetParent(s Shape)
@@ -21,18 +23,30 @@ type Shape interface {
 //NormalAt is the super class method to get the normal of an object, LocalNormalAt implements the specifics of
 // the shape subclasses
 func NormalAt(s Shape, point *algebra.Vector, hit *Intersection) *algebra.Vector {
-	localPoint := WorldToObject(s, point)
+	return NormalAtTime(s, point, hit, 0)
+}
+
+//NormalAtTime returns the normal of an object at the given time, moving shapes and groups use their transform
+// interpolated at that time
+func NormalAtTime(s Shape, point *algebra.Vector, hit *Intersection, time float64) *algebra.Vector {
+	localPoint := WorldToObjectAt(s, point, time)
 	localNormal, err := s.LocalNormalAt(localPoint, hit)
 	if err != nil {
 		panic(err)
 	}
-	return ObjectToWorld(s, localNormal)
+	return ObjectToWorldAt(s, localNormal, time)
 }
 
 //PatternAtObject takes a shape and pattern and the point the ray intersects in the world and returns what color should
 // be there given these parameters
 func PatternAtObject(s Shape, pattern *canvas.Pattern, worldPoint *algebra.Vector) *canvas.Color {
-	objectPoint := s.GetTransform().Inverse().MultiplyByVec(worldPoint)
+	return PatternAtObjectTime(s, pattern, worldPoint, 0)
+}
+
+//PatternAtObjectTime returns the pattern color like PatternAtObject for a shape seen at the given time,
+// so patterns move along with their shape
+func PatternAtObjectTime(s Shape, pattern *canvas.Pattern, worldPoint *algebra.Vector, time float64) *canvas.Color {
+	objectPoint := TransformAt(s, time).Inverse().MultiplyByVec(worldPoint)
 	patternPoint := pattern.Transform.Inverse().MultiplyByVec(objectPoint)
 	return pattern.GetColor(patternPoint)
 }
@@ -40,22 +54,33 @@ func PatternAtObject(s Shape, pattern *canvas.Pattern, worldPoint *algebra.Vecto
 // helpers
 
 func WorldToObject(s Shape, point *algebra.Vector) *algebra.Vector {
+	return WorldToObjectAt(s, point, 0)
+}
 
+//WorldToObjectAt converts a world space point to the object space of s using the transforms of s and its parents
+// at the given time
+func WorldToObjectAt(s Shape, point *algebra.Vector, time float64) *algebra.Vector {
 	if s.GetParent() != nil {
-		point = WorldToObject(s.GetParent(), point)
+		point = WorldToObjectAt(s.GetParent(), point, time)
 	}
-	return s.GetTransform().Inverse().MultiplyByVec(point)
+	return TransformAt(s, time).Inverse().MultiplyByVec(point)
 }
 
 func ObjectToWorld(s Shape, normal *algebra.Vector) *algebra.Vector {
-	normal = s.GetTransform().Inverse().Transpose().MultiplyByVec(normal)
+	return ObjectToWorldAt(s, normal, 0)
+}
+
+//ObjectToWorldAt converts a normal in the object space of s to world space using the transforms of s and its parents
+// at the given time
+func ObjectToWorldAt(s Shape, normal *algebra.Vector, time float64) *algebra.Vector {
+	normal = TransformAt(s, time).Inverse().Transpose().MultiplyByVec(normal)
 	normal.Get()[3] = 0
 	normal, err := normal.Normalize()
 	if err != nil {
 		panic(err)
 	}
 	if s.GetParent() != nil {
-		normal = ObjectToWorld(s.GetParent(), normal)
+		normal = ObjectToWorldAt(s.GetParent(), normal, time)
 	}
 	return normal
 }
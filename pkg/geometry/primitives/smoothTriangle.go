@@ -10,6 +10,7 @@ import (
 type SmoothTriangle struct {
 	Parent    Shape
 	transform *algebra.Matrix
+	motion    *Motion
 	material  *canvas.Material
 	p1        *algebra.Vector
 	p2        *algebra.Vector
@@ -141,6 +142,17 @@ func (t *SmoothTriangle) GetTransform() *algebra.Matrix {
 	return t.transform
 }
 
+//SetMotion Setter for the SmoothTriangle's motion over the shutter interval, nil makes it static
+func (t *SmoothTriangle) SetMotion(m *Motion) {
+	t.motion = m
+	RefreshBounds(t)
+}
+
+//GetMotion Getter for the SmoothTriangle's motion, nil when it is static
+func (t *SmoothTriangle) GetMotion() *Motion {
+	return t.motion
+}
+
 //GetMaterial Getter for material of SmoothTriangle Shape, interface method
 func (t *SmoothTriangle) GetMaterial() *canvas.Material {
 	return t.material
@@ -254,3 +266,20 @@ func (t *SmoothTriangle) LocalNormalAt(p *algebra.Vector, hit *Intersection) (*a
 	temp, err = temp.Add(t.n1.MultScalar(1 - hit.U - hit.V))
 	return temp, nil
 }
+
+//SurfaceArea returns the area of the triangle
+func (t *SmoothTriangle) SurfaceArea() float64 {
+	return triangleArea(t.e1, t.e2)
+}
+
+//SurfacePoint returns the point of the triangle at the coordinates u, v spread evenly over it and the normal of the
+// flat triangle, not the interpolated normal used for shading
+func (t *SmoothTriangle) SurfacePoint(u, v float64) (*algebra.Vector, *algebra.Vector) {
+	return trianglePoint(t.p1, t.e1, t.e2, u, v), triangleNormal(t.e1, t.e2)
+}
+
+//SurfacePointFacing returns the point of the triangle at the coordinates u, v with the normal of the side of the
+// triangle facing p and the area of the triangle
+func (t *SmoothTriangle) SurfacePointFacing(p *algebra.Vector, u, v float64) (*algebra.Vector, *algebra.Vector, float64) {
+	return facingTrianglePoint(t.p1, t.e1, t.e2, p, u, v)
+}
@@ -12,6 +12,7 @@ type Sphere struct {
 	origin    *algebra.Vector
 	radius    float64
 	transform *algebra.Matrix
+	motion    *Motion
 	material  *canvas.Material
 }
 
@@ -55,6 +56,17 @@ func (s *Sphere) GetTransform() *algebra.Matrix {
 	return s.transform
 }
 
+//SetMotion Setter for the Sphere's motion over the shutter interval, nil makes it static
+func (s *Sphere) SetMotion(m *Motion) {
+	s.motion = m
+	RefreshBounds(s)
+}
+
+//GetMotion Getter for the Sphere's motion, nil when it is static
+func (s *Sphere) GetMotion() *Motion {
+	return s.motion
+}
+
 //SetMaterial sets the Sphere's material
 func (s *Sphere) SetMaterial(m *canvas.Material) {
 	s.material = m
@@ -127,3 +139,42 @@ func (s *Sphere) LocalIntersect(r *algebra.Ray) ([]*Intersection, bool) {
 	t2 := (-b + math.Sqrt(discriminant)) / (2 * a)
 	return []*Intersection{NewIntersection(s, t1), NewIntersection(s, t2)}, true
 }
+
+//SurfaceArea returns the area of the unit sphere
+func (s *Sphere) SurfaceArea() float64 {
+	return 4 * math.Pi
+}
+
+//SurfacePoint returns the point of the unit sphere at height 1 - 2u and angle 2πv around the y axis and its normal
+func (s *Sphere) SurfacePoint(u, v float64) (*algebra.Vector, *algebra.Vector) {
+	y := 1 - 2*u
+	r := math.Sqrt(math.Max(0, 1-y*y))
+	phi := 2 * math.Pi * v
+	x, z := r*math.Cos(phi), r*math.Sin(phi)
+	return algebra.NewPoint(x, y, z), algebra.NewVector(x, y, z)
+}
+
+//SurfacePointFacing returns the point of the cap of the unit sphere seen from p at the coordinates u, v, spread
+// evenly over the cap, along with its normal and the area of the cap. A point p inside the sphere sees no part of it
+func (s *Sphere) SurfacePointFacing(p *algebra.Vector, u, v float64) (*algebra.Vector, *algebra.Vector, float64) {
+	toP := algebra.NewVector(p.Get()[0], p.Get()[1], p.Get()[2])
+	d := toP.Magnitude()
+	if d <= 1 {
+		point, normal := s.SurfacePoint(u, v)
+		return point, normal, 0
+	}
+	axis, err := toP.Normalize()
+	if err != nil {
+		panic(err)
+	}
+	x, y := algebra.OrthonormalBasis(axis)
+	// the cap is where the height along the axis is above 1/d, heights are spread evenly over a sphere
+	h := 1 - u*(1-1/d)
+	r := math.Sqrt(math.Max(0, 1-h*h))
+	phi := 2 * math.Pi * v
+	n := make([]float64, 3)
+	for i := range n {
+		n[i] = h*axis.Get()[i] + r*math.Cos(phi)*x.Get()[i] + r*math.Sin(phi)*y.Get()[i]
+	}
+	return algebra.NewPoint(n...), algebra.NewVector(n...), 2 * math.Pi * (1 - 1/d)
+}
@@ -9,6 +9,7 @@ import (
 type Triangle struct {
 	parent    Shape
 	transform *algebra.Matrix
+	motion    *Motion
 	material  *canvas.Material
 	p1        *algebra.Vector
 	p2        *algebra.Vector
@@ -54,6 +55,17 @@ func (t *Triangle) GetTransform() *algebra.Matrix {
 	return t.transform
 }
 
+//SetMotion Setter for the Triangle's motion over the shutter interval, nil makes it static
+func (t *Triangle) SetMotion(m *Motion) {
+	t.motion = m
+	RefreshBounds(t)
+}
+
+//GetMotion Getter for the Triangle's motion, nil when it is static
+func (t *Triangle) GetMotion() *Motion {
+	return t.motion
+}
+
 //GetMaterial Getter for Triangle Shape material canvas.Material
 func (t *Triangle) GetMaterial() *canvas.Material {
 	return t.material
@@ -160,3 +172,75 @@ func (t *Triangle) LocalIntersect(r *algebra.Ray) ([]*Intersection, bool) {
 func (t *Triangle) LocalNormalAt(p *algebra.Vector, hit *Intersection) (*algebra.Vector, error) {
 	return t.normal, nil
 }
+
+//SurfaceArea returns the area of the triangle
+func (t *Triangle) SurfaceArea() float64 {
+	return triangleArea(t.e1, t.e2)
+}
+
+//SurfacePoint returns the point of the triangle at the coordinates u, v spread evenly over it and the normal of the
+// triangle
+func (t *Triangle) SurfacePoint(u, v float64) (*algebra.Vector, *algebra.Vector) {
+	return trianglePoint(t.p1, t.e1, t.e2, u, v), t.normal
+}
+
+//SurfacePointFacing returns the point of the triangle at the coordinates u, v with the normal of the side of the
+// triangle facing p and the area of the triangle
+func (t *Triangle) SurfacePointFacing(p *algebra.Vector, u, v float64) (*algebra.Vector, *algebra.Vector, float64) {
+	return facingTrianglePoint(t.p1, t.e1, t.e2, p, u, v)
+}
+
+//triangleArea returns the area of the triangle with edges e1 and e2
+func triangleArea(e1, e2 *algebra.Vector) float64 {
+	cross, err := algebra.CrossProduct(e1, e2)
+	if err != nil {
+		panic(err)
+	}
+	return cross.Magnitude() / 2
+}
+
+//triangleNormal returns the unit normal of the triangle with edges e1 and e2
+func triangleNormal(e1, e2 *algebra.Vector) *algebra.Vector {
+	normal, err := algebra.CrossProduct(e1, e2)
+	if err != nil {
+		panic(err)
+	}
+	normal, err = normal.Normalize()
+	if err != nil {
+		panic(err)
+	}
+	return normal
+}
+
+//trianglePoint maps u, v in [0, 1) to a point of the triangle p1, p1 + e1, p1 + e2 such that evenly spread u, v
+// give evenly spread points
+func trianglePoint(p1, e1, e2 *algebra.Vector, u, v float64) *algebra.Vector {
+	su := math.Sqrt(u)
+	p, err := p1.Add(e1.MultScalar(su * (1 - v)))
+	if err != nil {
+		panic(err)
+	}
+	p, err = p.Add(e2.MultScalar(su * v))
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+//facingTrianglePoint returns the point of the triangle p1, p1 + e1, p1 + e2 mapped from u, v by trianglePoint, the
+// normal of the side of the triangle facing p and the area of the triangle. Both sides of a triangle face outwards
+func facingTrianglePoint(p1, e1, e2, p *algebra.Vector, u, v float64) (*algebra.Vector, *algebra.Vector, float64) {
+	normal := triangleNormal(e1, e2)
+	toP, err := p.Subtract(p1)
+	if err != nil {
+		panic(err)
+	}
+	side, err := algebra.DotProduct(normal, toP)
+	if err != nil {
+		panic(err)
+	}
+	if side < 0 {
+		normal = normal.Negate()
+	}
+	return trianglePoint(p1, e1, e2, u, v), normal, triangleArea(e1, e2)
+}
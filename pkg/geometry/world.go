@@ -9,15 +9,24 @@ import (
 	"reflect"
 )
 
-//World manages the world space of the Shape(s) inside of it and the light sources illuminating it
+//World manages the world space of the Shape(s) inside of it and the light sources illuminating it.
+// Once built, a World is only read while rendering so ColorAt can be called from several goroutines at once
 type World struct {
 	Objects []primitives.Shape
-	Lights  []*canvas.PointLight
+	Lights  []canvas.Light
+	// Background is seen by every ray that misses all the objects, reflected and refracted rays included. Rays that
+	// miss are black without one. The PathTracer also samples a canvas.SampledBackground as a light, and ShadeHit
+	// lights surfaces with a canvas.IrradianceBackground such as a canvas.Sky
+	Background canvas.Background
+	// Occlusion darkens the ambient term of ShadeHit, and the light of a canvas.IrradianceBackground, at hits hidden
+	// by nearby objects. The ambient term is flat without one
+	Occlusion *AmbientOcclusion
+	photons   *PhotonMap // nil unless set with SetPhotonMap
 }
 
 //NewDefaultWorld creates a new default world with one light source and 2 spheres
 func NewDefaultWorld() *World {
-	lights := make([]*canvas.PointLight, 0, 0)
+	lights := make([]canvas.Light, 0, 0)
 	light := canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-10, 10, -10))
 	lights = append(lights, light)
 
@@ -47,72 +56,165 @@ func (w *World) Intersect(r *algebra.Ray) *primitives.Intersections {
 	return is
 }
 
-//ShadeHit Determines the color at some valid ray intersection (hit)
+//ShadeHit Determines the color at some valid ray intersection (hit), glowing surfaces add the light they emit
 func (w World) ShadeHit(comps Comps, depth int) *canvas.Color {
+	material := comps.Object.GetMaterial()
+	color := material.Emitted()
+	var patternColor *canvas.Color
+	if material.Pattern != nil {
+		patternColor = primitives.PatternAtObjectTime(comps.Object, material.Pattern, comps.Point, comps.Time)
+	}
+	albedo := material.Color
+	if patternColor != nil {
+		albedo = patternColor
+	}
+	lit, accessibility := material, 1.0
+	if w.Occlusion != nil {
+		du, dv := pointOffsets(comps.OverPoint)
+		accessibility = w.Occlusion.accessibility(&w, &comps, du, dv)
+		occluded := *material
+		occluded.Ambient *= accessibility
+		lit = &occluded
+	}
+	color = color.Add(w.lighting(comps, lit, patternColor))
+	color = color.Add(w.skyLighting(comps, material, albedo).ScalarMult(accessibility))
+	if w.photons != nil {
+		color = color.Add(w.photons.radiance(&comps, material, albedo))
+	}
+
+	reflected := w.ReflectedColor(&comps, depth)
+	refracted := w.RefractedColor(&comps, depth)
+	if material.Reflective > 0 && material.Transparency > 0 {
+		reflectance := Schlick(&comps)
+		color = color.Add(reflected.ScalarMult(reflectance))
+		color = color.Add(refracted.ScalarMult(1 - reflectance))
+	} else {
+		color = color.Add(refracted)
+		color = color.Add(reflected)
+	}
+	return color
+}
+
+//lighting returns the light of every light of the World reflected by the material at the hit, each light only
+// lights the hit as much as it is visible from it
+func (w World) lighting(comps Comps, material *canvas.Material, patternColor *canvas.Color) *canvas.Color {
 	color := &canvas.Color{0, 0, 0}
-	inShadow := w.PointIsShadowed(comps.OverPoint)
 	for _, l := range w.Lights {
-		pattern := comps.Object.GetMaterial().Pattern
-		var patternColor *canvas.Color
-		if pattern != nil {
-			patternColor = primitives.PatternAtObject(comps.Object, pattern, comps.Point)
-		} else {
-			patternColor = nil
-		}
-		lightingColor := canvas.Lighting(comps.Object.GetMaterial(), patternColor, l, comps.Point, comps.Eye, comps.Normal, inShadow)
+		samples, weights := canvas.LightSamples(l, comps.OverPoint)
+		visibility := w.lightVisibilityAt(comps.OverPoint, l, samples, weights, comps.Time)
+		lightingColor := canvas.LightingSamples(material, patternColor, l, samples, weights, comps.Point, comps.Eye,
+			comps.Normal, visibility)
 		color = color.Add(lightingColor)
-
-		reflected := w.ReflectedColor(&comps, depth)
-		refracted := w.RefractedColor(&comps, depth)
-		material := comps.Object.GetMaterial()
-		if material.Reflective > 0 && material.Transparency > 0 {
-			reflectance := Schlick(&comps)
-			color = color.Add(reflected.ScalarMult(reflectance))
-			color = color.Add(refracted.ScalarMult(1 - reflectance))
-		} else {
-			color = color.Add(refracted)
-			color = color.Add(reflected)
-		}
 	}
 	return color
 }
 
+//skyLighting returns the light of a canvas.IrradianceBackground, such as a canvas.Sky, diffusely reflected by the
+// material at the hit. Like the ambient term it is not shadowed. Its irradiance is reflected like the light of the
+// World's lights by the Phong model, without the 1/pi of a Lambertian surface, so the sky and its sun keep their
+// proportions
+func (w World) skyLighting(comps Comps, material *canvas.Material, albedo *canvas.Color) *canvas.Color {
+	sky, ok := w.Background.(canvas.IrradianceBackground)
+	if !ok {
+		return &canvas.Color{0, 0, 0}
+	}
+	irradiance := sky.Irradiance(comps.Normal)
+	return canvas.Multiply(albedo, irradiance).ScalarMult(material.Diffuse)
+}
+
 //ColorAt returns the color where the ray intersects (if at all), with a maximum recursive depth of depth
 func (w World) ColorAt(ray *algebra.Ray, depth int) *canvas.Color {
 	intersections := w.Intersect(ray)
 	if h := intersections.Hit(); h == nil {
-		return &canvas.Color{0, 0, 0}
+		return w.background(ray)
 	} else {
 		c := PrepareComputations(h, ray, intersections)
 		return w.ShadeHit(*c, depth)
 	}
 }
 
+//background returns the color seen by the ray when it misses every object
+func (w World) background(ray *algebra.Ray) *canvas.Color {
+	if w.Background == nil {
+		return &canvas.Color{0, 0, 0}
+	}
+	return w.Background.ColorAt(ray.Get()["direction"])
+}
+
 //PointIsShadowed returns whether or not the point in question is in the shadow of some other object
 func (w World) PointIsShadowed(p *algebra.Vector) bool {
-	for i := 0; i < len(w.Lights); i++ {
-		v, err := w.Lights[i].Position.Subtract(p)
-		if err != nil {
-			panic(err)
-			return false
-		}
+	return w.pointIsShadowedAt(p, 0)
+}
 
-		dist := v.Magnitude()
-		direction, err := v.Normalize()
-		if err != nil {
-			panic(err)
-			return false
-		}
-		res := append(p.Get()[:3], direction.Get()[:3]...)
-		r := algebra.NewRay(res...)
-		is := w.Intersect(r)
-		if h := is.Hit(); h != nil && h.T < dist {
+//pointIsShadowedAt tests the shadow at point p with objects placed where they are at the given time. The point is
+// in shadow when no light from one of the lights reaches it
+func (w World) pointIsShadowedAt(p *algebra.Vector, time float64) bool {
+	for _, l := range w.Lights {
+		samples, weights := canvas.LightSamples(l, p)
+		v := w.lightVisibilityAt(p, l, samples, weights, time)
+		if v.Red() <= 0 && v.Green() <= 0 && v.Blue() <= 0 {
 			return true
 		}
 	}
 	return false
 }
 
+//LightVisibility returns the fraction of the light reaching point p for each color channel, from 0 when p is fully
+// in shadow to 1 when it is fully lit. Points in the penumbra of an area light see some of its samples, and points in
+// the shadow of transparent objects get the part of the light passing through them
+func (w World) LightVisibility(p *algebra.Vector, light canvas.Light) *canvas.Color {
+	samples, weights := canvas.LightSamples(light, p)
+	return w.lightVisibilityAt(p, light, samples, weights, 0)
+}
+
+//lightVisibilityAt returns the visibility of the given samples of the light with objects placed where they are at
+// the given time. Samples count in proportion to their weights, for each color channel, when weights is not nil
+func (w World) lightVisibilityAt(p *algebra.Vector, light canvas.Light, samples []*algebra.Vector, weights []*canvas.Color, time float64) *canvas.Color {
+	visibility := &canvas.Color{0, 0, 0}
+	total := &canvas.Color{0, 0, 0}
+	for k, sample := range samples {
+		weight := &canvas.Color{1, 1, 1}
+		if weights != nil {
+			weight = weights[k]
+		}
+		if weight[0] <= 0 && weight[1] <= 0 && weight[2] <= 0 {
+			continue
+		}
+		visibility = visibility.Add(canvas.Multiply(w.transmittance(p, light, sample, time), weight))
+		total = total.Add(weight)
+	}
+	for i := range visibility {
+		if total[i] > 0 {
+			visibility[i] /= total[i]
+		}
+	}
+	return visibility
+}
+
+//transmittance returns the fraction of the light going from the sample of the light to the point p at the given
+// time. Opaque objects block the light while transparent objects let their Transparency through, tinted by their
+// color, once for every object crossed. Transparent objects block the light too when caustic photons carry the light
+// passing through them
+func (w World) transmittance(p *algebra.Vector, light canvas.Light, sample *algebra.Vector, time float64) *canvas.Color {
+	r, dist := light.ShadowRay(p, sample)
+	r.SetTime(time)
+	is := w.Intersect(r)
+	result := &canvas.Color{1, 1, 1}
+	crossed := make(map[primitives.Shape]bool)
+	for _, h := range is.GetHits().Get() {
+		if h.T >= dist || crossed[h.Object] {
+			continue
+		}
+		crossed[h.Object] = true
+		material := h.Object.GetMaterial()
+		if material.Transparency <= 0 || w.photons.carries(h.Object) {
+			return &canvas.Color{0, 0, 0}
+		}
+		result = canvas.Multiply(result, material.Color.ScalarMult(material.Transparency))
+	}
+	return result
+}
+
 //ReflectedColor determines if there is a reflected color being emitted at some ray intersection
 // Takes the pre-computed computations at the ray intersection (struct Comps)
 func (w *World) ReflectedColor(comps *Comps, depth int) *canvas.Color {
@@ -123,6 +225,7 @@ func (w *World) ReflectedColor(comps *Comps, depth int) *canvas.Color {
 	d := []float64{comps.Reflect.Get()[0], comps.Reflect.Get()[1], comps.Reflect.Get()[2]}
 	res := append(p, d...)
 	reflectRay := algebra.NewRay(res...)
+	reflectRay.SetTime(comps.Time)
 	color := w.ColorAt(reflectRay, depth-1)
 	return color.ScalarMult(comps.Object.GetMaterial().Reflective)
 }
@@ -134,27 +237,38 @@ func (w *World) RefractedColor(comps *Comps, depth int) *canvas.Color {
 	if comps.Object.GetMaterial().Transparency == 0.0 || depth == 0 {
 		return &canvas.Color{0, 0, 0}
 	}
+	direction := refractedDirection(comps)
+	// Total reflection occurs
+	if direction == nil {
+		return &canvas.Color{0, 0, 0}
+	}
+
+	point := []float64{comps.UnderPoint.Get()[0], comps.UnderPoint.Get()[1], comps.UnderPoint.Get()[2]}
+	res := append(point, direction.Get()[:3]...)
+	refractRay := algebra.NewRay(res...)
+	refractRay.SetTime(comps.Time)
+	color := w.ColorAt(refractRay, depth-1).ScalarMult(comps.Object.GetMaterial().Transparency)
+	return color
+}
+
+//refractedDirection returns the direction of the ray refracted at the hit described by comps, nil on total
+// internal reflection
+func refractedDirection(comps *Comps) *algebra.Vector {
 	refractiveRatio := comps.N1 / comps.N2
 	cosI, err := algebra.DotProduct(comps.Eye, comps.Normal)
 	if err != nil {
 		panic(err)
 	}
 	sin2T := refractiveRatio * refractiveRatio * (1 - cosI*cosI)
-	// Total reflection occurs
 	if sin2T > 1 {
-		return &canvas.Color{0, 0, 0}
+		return nil
 	}
-
 	cosT := math.Sqrt(1.0 - sin2T)
 	direction, err := comps.Normal.MultScalar(refractiveRatio*cosI - cosT).Subtract(comps.Eye.MultScalar(refractiveRatio))
 	if err != nil {
 		panic(err)
 	}
-	point := comps.UnderPoint.Get()[:3]
-	res := append(point, direction.Get()[:3]...)
-	refractRay := algebra.NewRay(res...)
-	color := w.ColorAt(refractRay, depth-1).ScalarMult(comps.Object.GetMaterial().Transparency)
-	return color
+	return direction
 }
 
 //Schlick returns the reflectance at a pre-computed ray intersection based on the Schlick model
@@ -193,12 +307,14 @@ type Comps struct {
 	Normal     *algebra.Vector
 	Reflect    *algebra.Vector
 	Inside     bool
+	Time       float64 // time the ray was cast at, secondary rays are cast at the same instant
 }
 
 func PrepareComputations(intersection *primitives.Intersection, ray *algebra.Ray, is *primitives.Intersections) *Comps {
 	position := ray.Position(intersection.T)
-	c := &Comps{T: intersection.T, Object: intersection.Object, Point: position,
-		Eye: ray.Get()["direction"].Negate(), Normal: primitives.NormalAt(intersection.Object, position, intersection)}
+	c := &Comps{T: intersection.T, Object: intersection.Object, Point: position, Time: ray.Time(),
+		Eye:    ray.Get()["direction"].Negate(),
+		Normal: primitives.NormalAtTime(intersection.Object, position, intersection, ray.Time())}
 
 	if d, err := algebra.DotProduct(c.Normal, c.Eye); err != nil {
 		panic(err)
@@ -107,7 +107,7 @@ func TestWorld_ShadeHit(t *testing.T) {
 	}
 
 	//shadow test
-	lights := make([]*canvas.PointLight, 0, 0)
+	lights := make([]canvas.Light, 0, 0)
 	l := canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(0, 0, -10))
 	lights = append(lights, l)
 	objs := make([]primitives.Shape, 0, 0)
@@ -190,7 +190,7 @@ func TestWorld_ColorAt(t *testing.T) {
 	}
 
 	// test that an "infinite recursion" terminates
-	lights := make([]*canvas.PointLight, 0, 0)
+	lights := make([]canvas.Light, 0, 0)
 	light1 := canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(0, 0, 0))
 	lower := primitives.NewPlane(algebra.TranslationMatrix(0, -1, 0))
 	m := canvas.NewDefaultMaterial()
@@ -232,6 +232,24 @@ func TestWorld_ReflectedColor(t *testing.T) {
 	testColorEquals(t, color, &canvas.Color{0.19032, 0.2379, 0.14274})
 }
 
+func TestWorld_ShadeHitSeveralLights(t *testing.T) {
+	w := NewDefaultWorld()
+	shape := primitives.NewPlane(algebra.TranslationMatrix(0, -1, 0))
+	m := canvas.NewDefaultMaterial()
+	m.Reflective = 0.5
+	shape.SetMaterial(m)
+	w.Objects = append(w.Objects, shape)
+	r := algebra.NewRay(0, 0, -3, 0, -math.Sqrt(2)/2, math.Sqrt(2)/2)
+	i := primitives.NewIntersection(shape, math.Sqrt(2))
+	comps := PrepareComputations(i, r, nil)
+	one := w.ShadeHit(*comps, 1).Subtract(w.ReflectedColor(comps, 1))
+
+	// a second identical light doubles the light on the surface, the reflection is only added once
+	w.Lights = append(w.Lights, w.Lights[0])
+	reflected := w.ReflectedColor(comps, 1)
+	testColorEquals(t, w.ShadeHit(*comps, 1), one.ScalarMult(2).Add(reflected))
+}
+
 //Test prepare computations for N1, N2 refractive indexes
 func TestRefractiveComputations(t *testing.T) {
 	A := primitives.NewGlassSphere(algebra.ScalingMatrix(2, 2, 2), 1.5)
@@ -370,7 +388,8 @@ func TestWorld_RefractedColor(t *testing.T) {
 	xs.GetHits().Push(i)
 	comps = PrepareComputations(i, r, xs)
 	color := w.ShadeHit(*comps, 5)
-	testColorEquals(t, color, &canvas.Color{0.93642, 0.68642, 0.68642})
+	// the ball is lit through the half transparent floor
+	testColorEquals(t, color, &canvas.Color{1.12547, 0.68642, 0.68642})
 }
 
 func TestSchlick(t *testing.T) {
@@ -431,7 +450,8 @@ func TestSchlick(t *testing.T) {
 	xs.GetHits().Push(i1)
 	comps = PrepareComputations(i1, r, xs)
 	color := w.ShadeHit(*comps, 5)
-	testColorEquals(t, color, &canvas.Color{0.93391, 0.69643, 0.69243})
+	// the ball is lit through the half transparent floor
+	testColorEquals(t, color, &canvas.Color{1.11500, 0.69643, 0.69243})
 }
 
 func TestWorld_PointIsShadowed(t *testing.T) {
@@ -466,3 +486,253 @@ func testRefractiveIndexes(t *testing.T, n1, n2, expected1, expected2 float64) {
 		t.Errorf("Expected  %f n1, Got: %f . Expected %f n2, Got: %f", expected1, n1, expected2, n2)
 	}
 }
+
+func TestWorld_LightVisibility(t *testing.T) {
+	w := NewDefaultWorld()
+	light := w.Lights[0]
+	tests := []struct {
+		point      *algebra.Vector
+		visibility float64
+	}{
+		{algebra.NewPoint(0, 1.0001, 0), 1},
+		{algebra.NewPoint(-1.0001, 0, 0), 1},
+		{algebra.NewPoint(0, 0, -1.0001), 1},
+		{algebra.NewPoint(0, 0, 1.0001), 0},
+		{algebra.NewPoint(1.0001, 0, 0), 0},
+		{algebra.NewPoint(0, -1.0001, 0), 0},
+	}
+	for _, test := range tests {
+		testColorEquals(t, w.LightVisibility(test.point, light), &canvas.Color{test.visibility, test.visibility, test.visibility})
+	}
+
+	area := canvas.NewRectangleLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-0.5, -0.5, -5),
+		algebra.NewVector(1, 0, 0), 2, algebra.NewVector(0, 1, 0), 2, false)
+	tests = []struct {
+		point      *algebra.Vector
+		visibility float64
+	}{
+		{algebra.NewPoint(0, 0, 2), 0},
+		{algebra.NewPoint(1, -1, 2), 0.25},
+		{algebra.NewPoint(1.5, 0, 2), 0.5},
+		{algebra.NewPoint(1.25, 1.25, 3), 0.75},
+		{algebra.NewPoint(0, 0, -2), 1},
+	}
+	for _, test := range tests {
+		testColorEquals(t, w.LightVisibility(test.point, area), &canvas.Color{test.visibility, test.visibility, test.visibility})
+	}
+}
+
+func TestWorld_ShadeHitAreaLight(t *testing.T) {
+	w := NewDefaultWorld()
+	w.Lights = []canvas.Light{canvas.NewRectangleLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(-0.5, -0.5, -5),
+		algebra.NewVector(1, 0, 0), 2, algebra.NewVector(0, 1, 0), 2, false)}
+	r := algebra.NewRay(0, 0, -5, 0, 0, 1)
+	shape := w.Objects[0]
+	i := primitives.NewIntersection(shape, 4)
+	xs := primitives.NewIntersections()
+	xs.GetHits().Push(i)
+	comps := PrepareComputations(i, r, xs)
+	lit := w.ShadeHit(*comps, 0)
+	if lit.Red() <= 0.1*0.8 {
+		t.Errorf("Expected the area light to light the sphere facing it, got %v", lit)
+	}
+
+	// the far side of the larger sphere only gets the ambient term
+	r = algebra.NewRay(0, 0, 5, 0, 0, -1)
+	i = primitives.NewIntersection(shape, 4)
+	xs = primitives.NewIntersections()
+	xs.GetHits().Push(i)
+	comps = PrepareComputations(i, r, xs)
+	testColorEquals(t, w.ShadeHit(*comps, 0), &canvas.Color{0.08, 0.1, 0.06})
+}
+
+func TestWorld_ShadeHitSpotLight(t *testing.T) {
+	w := NewDefaultWorld()
+	light := canvas.NewSpotLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(0, 0, -5), algebra.NewVector(0, 0, 1),
+		math.Pi/12, math.Pi/6)
+	w.Lights = []canvas.Light{light}
+	r := algebra.NewRay(0, 0, -5, 0, 0, 1)
+	shape := w.Objects[0]
+	i := primitives.NewIntersection(shape, 4)
+	xs := primitives.NewIntersections()
+	xs.GetHits().Push(i)
+	comps := PrepareComputations(i, r, xs)
+	lit := w.ShadeHit(*comps, 0)
+	if lit.Red() <= 0.1*0.8 {
+		t.Errorf("Expected the spot light to light the sphere in its cone, got %v", lit)
+	}
+
+	// turned away, the spot light gives no light, not even the ambient term
+	light.Direction = algebra.NewVector(0, 1, 0)
+	testColorEquals(t, w.ShadeHit(*comps, 0), &canvas.Color{0, 0, 0})
+
+	// occluders of a spot light cast shadows
+	if !w.PointIsShadowed(algebra.NewPoint(0, 0, 10)) {
+		t.Errorf("Expected the point behind the spheres to be in the shadow of the spot light")
+	}
+}
+
+func TestWorld_PointIsShadowedDirectional(t *testing.T) {
+	w := NewDefaultWorld()
+	w.Lights = []canvas.Light{canvas.NewDirectionalLight(&canvas.Color{1, 1, 1}, algebra.NewVector(0, -1, 0))}
+	w.Objects = append(w.Objects, primitives.NewSphere(algebra.TranslationMatrix(20, 1000, 0)))
+
+	if !w.PointIsShadowed(algebra.NewPoint(0, -10, 0)) {
+		t.Errorf("Expected the point under the spheres to be in shadow")
+	}
+	// shadow rays of a directional light have no maximum distance
+	if !w.PointIsShadowed(algebra.NewPoint(20, 10, 0)) {
+		t.Errorf("Expected the point under a distant sphere to be in shadow")
+	}
+	if w.PointIsShadowed(algebra.NewPoint(10, 10, 0)) {
+		t.Errorf("Expected the point with nothing above it to be lit")
+	}
+}
+
+func TestWorld_LightVisibilityTransparent(t *testing.T) {
+	w := NewDefaultWorld()
+	light := canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(0, 10, 0))
+	w.Lights = []canvas.Light{light}
+	glass := primitives.NewSphere(algebra.TranslationMatrix(0, 5, 0))
+	m := glass.GetMaterial()
+	m.Color = &canvas.Color{1, 0.5, 0.5}
+	m.Transparency = 0.8
+	glass.SetMaterial(m)
+	w.Objects = []primitives.Shape{glass}
+
+	// the glass lets through part of the light, tinted by its color, and is only counted once
+	testColorEquals(t, w.LightVisibility(algebra.NewPoint(0, 0, 0), light), &canvas.Color{0.8, 0.4, 0.4})
+	testColorEquals(t, w.LightVisibility(algebra.NewPoint(3, 0, 0), light), &canvas.Color{1, 1, 1})
+	if w.PointIsShadowed(algebra.NewPoint(0, 0, 0)) {
+		t.Errorf("Expected the shadow of a transparent object not to be fully dark")
+	}
+
+	// an opaque object behind the glass blocks the light
+	w.Objects = append(w.Objects, primitives.NewSphere(algebra.TranslationMatrix(0, 2, 0)))
+	testColorEquals(t, w.LightVisibility(algebra.NewPoint(0, 0, 0), light), &canvas.Color{0, 0, 0})
+}
+
+func TestWorld_ShadeHitPerLightShadows(t *testing.T) {
+	w := NewDefaultWorld()
+	w.Objects = append(w.Objects, primitives.NewSphere(algebra.TranslationMatrix(0, 0, 10)))
+	// the second sphere is only in the shadow of the first light
+	w.Lights = []canvas.Light{
+		canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(0, 0, -10)),
+		canvas.NewPointLight(&canvas.Color{1, 1, 1}, algebra.NewPoint(0, 10, 10)),
+	}
+	r := algebra.NewRay(0, 5, 10, 0, -1, 0)
+	shape := w.Objects[2]
+	i := primitives.NewIntersection(shape, 4)
+	xs := primitives.NewIntersections()
+	xs.GetHits().Push(i)
+	comps := PrepareComputations(i, r, xs)
+
+	shadowed := w.ShadeHit(*comps, 0)
+	w.Lights = w.Lights[1:]
+	testColorEquals(t, shadowed, w.ShadeHit(*comps, 0).Add(&canvas.Color{0.1, 0.1, 0.1}))
+}
+
+func TestWorld_ShadeHitEmissive(t *testing.T) {
+	w := NewDefaultWorld()
+	w.Lights = nil
+	m := w.Objects[0].GetMaterial()
+	m.Emission = &canvas.Color{1, 1, 1}
+	m.EmissionStrength = 2
+	r := algebra.NewRay(0, 0, -5, 0, 0, 1)
+	i := primitives.NewIntersection(w.Objects[0], 4)
+	xs := primitives.NewIntersections()
+	xs.GetHits().Push(i)
+	comps := PrepareComputations(i, r, xs)
+	testColorEquals(t, w.ShadeHit(*comps, 0), &canvas.Color{2, 2, 2})
+}
+
+func TestWorld_ShadeHitGeometryLight(t *testing.T) {
+	floor := primitives.NewPlane(nil)
+	lamp := primitives.NewSphere(algebra.Multiply(algebra.TranslationMatrix(0, 5, 0),
+		algebra.ScalingMatrix(0.5, 0.5, 0.5)))
+	m := canvas.NewDefaultMaterial()
+	m.Emission = &canvas.Color{1, 1, 1}
+	m.EmissionStrength = 1
+	lamp.SetMaterial(m)
+	matte := canvas.NewDefaultMaterial()
+	matte.Specular = 0
+	floor.SetMaterial(matte)
+	w := &World{Objects: []primitives.Shape{floor, lamp}}
+	light, err := primitives.NewGeometryLight(lamp, 16, false)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+	w.Lights = []canvas.Light{light}
+
+	// the floor under the lamp only samples the part of the sphere facing it, which it fully sees
+	r := algebra.NewRay(0, 1, 0, 0, -1, 0)
+	i := primitives.NewIntersection(floor, 1)
+	xs := primitives.NewIntersections()
+	xs.GetHits().Push(i)
+	comps := PrepareComputations(i, r, xs)
+	testColorEquals(t, w.LightVisibility(comps.OverPoint, light), &canvas.Color{1, 1, 1})
+	// a sphere of radius 0.5 at a distance of 5 gives the light of its surface times pi * (0.5 / 5)^2, without any
+	// ambient term
+	lit := w.ShadeHit(*comps, 0)
+	if expected := 0.9 * math.Pi / 100; math.Abs(lit.Red()-expected) > 0.002 {
+		t.Errorf("Expected the lamp to light the floor with %f, got %v", expected, lit)
+	}
+
+	// the lamp's glow is seen directly
+	r = algebra.NewRay(0, 5, -5, 0, 0, 1)
+	if c := w.ColorAt(r, 0); c.Red() < 1 {
+		t.Errorf("Expected the lamp to glow, got %v", c)
+	}
+}
+
+func TestWorld_ColorAtBackground(t *testing.T) {
+	image := canvas.NewCanvas(2, 1)
+	image.Pixels[0][0] = &canvas.Color{1, 0, 0}
+	image.Pixels[0][1] = &canvas.Color{0, 0, 1}
+	w := NewDefaultWorld()
+	w.Background = canvas.NewEnvironmentMap(image, 1)
+
+	// the right half of the map is seen looking down -x
+	r := algebra.NewRay(0, 5, 0, -1, 0, 0)
+	testColorEquals(t, w.ColorAt(r, 3), &canvas.Color{0, 0, 1})
+	w.Background = nil
+	testColorEquals(t, w.ColorAt(r, 3), &canvas.Color{0, 0, 0})
+}
+
+func TestWorld_ReflectedBackground(t *testing.T) {
+	floor := primitives.NewPlane(nil)
+	m := canvas.NewDefaultMaterial()
+	m.Color = &canvas.Color{0, 0, 0}
+	m.Specular = 0
+	m.Reflective = 1
+	floor.SetMaterial(m)
+	w := &World{Objects: []primitives.Shape{floor},
+		Background: canvas.NewGradientBackground(&canvas.Color{0, 0, 0}, &canvas.Color{0, 0, 1})}
+
+	// the mirror reflects the top of the sky
+	r := algebra.NewRay(0, 1, 0, 0, -1, 0)
+	testColorEquals(t, w.ColorAt(r, 1), &canvas.Color{0, 0, 1})
+	testColorEquals(t, w.ColorAt(r, 0), &canvas.Color{0, 0, 0})
+}
+
+func TestWorld_ShadeHitSky(t *testing.T) {
+	floor := primitives.NewPlane(nil)
+	m := canvas.NewDefaultMaterial()
+	m.Color = &canvas.Color{0.5, 0.5, 0.5}
+	m.Ambient = 0
+	m.Specular = 0
+	floor.SetMaterial(m)
+	sky := canvas.NewSky(0.8, 0, 3, 1)
+	w := &World{Objects: []primitives.Shape{floor}, Background: sky}
+
+	// without lights the floor only reflects the light of the sky
+	r := algebra.NewRay(0, 1, 0, 0, -1, 0)
+	skylight := canvas.Multiply(m.Color, sky.Irradiance(algebra.NewVector(0, 1, 0))).ScalarMult(m.Diffuse)
+	testColorEquals(t, w.ColorAt(r, 0), skylight)
+
+	// the sun adds its light, its irradiance reflected like the irradiance of the sky
+	w.Lights = []canvas.Light{sky.Sun()}
+	sunlight := canvas.Multiply(m.Color, sky.Sun().GetIntensity()).ScalarMult(m.Diffuse * math.Sin(0.8))
+	testColorEquals(t, w.ColorAt(r, 0), skylight.Add(sunlight))
+}